@@ -111,6 +111,20 @@ func NewPullHandler(printer *output.Printer, format option.Format, path string,
 	return statusHandler, metadataHandler, nil
 }
 
+// NewVerifyHandler returns a metadata handler for verify command.
+func NewVerifyHandler(printer *output.Printer, format option.Format, path string) (metadata.VerifyHandler, error) {
+	switch format.Type {
+	case option.FormatTypeText.Name:
+		return text.NewVerifyHandler(printer), nil
+	case option.FormatTypeJSON.Name:
+		return json.NewVerifyHandler(printer, path), nil
+	case option.FormatTypeGoTemplate.Name:
+		return template.NewVerifyHandler(printer, path, format.Template), nil
+	default:
+		return nil, errors.UnsupportedFormatTypeError(format.Type)
+	}
+}
+
 // NewDiscoverHandler returns status and metadata handlers for discover command.
 func NewDiscoverHandler(out io.Writer, format option.Format, path string, rawReference string, desc ocispec.Descriptor, verbose bool) (metadata.DiscoverHandler, error) {
 	var handler metadata.DiscoverHandler
@@ -56,10 +56,25 @@ type PullHandler interface {
 	OnLayerSkipped(ocispec.Descriptor) error
 	// OnFilePulled is called after a file is pulled.
 	OnFilePulled(name string, outputDir string, desc ocispec.Descriptor, descPath string) error
+	// OnPlatformPulled is called after the files of a platform in an index are
+	// pulled into outputDir.
+	OnPlatformPulled(platform ocispec.Platform, outputDir string, desc ocispec.Descriptor, descPath string) error
+	// OnFileRemoved is called after a stale file not in the pulled artifact
+	// is removed.
+	OnFileRemoved(path string) error
 	// OnCompleted is called when the pull cmd execution is completed.
 	OnCompleted(opts *option.Target, desc ocispec.Descriptor) error
 }
 
+// VerifyHandler handles metadata output for verify events.
+type VerifyHandler interface {
+	// OnDifference is called when a file in the verified directory differs
+	// from the artifact, with status being missing, modified or extra.
+	OnDifference(path string, status string) error
+	// OnCompleted is called when the verification is completed.
+	OnCompleted(opts *option.Target, desc ocispec.Descriptor, dir string) error
+}
+
 // TaggedHandler handles status output for tag command.
 type TaggedHandler interface {
 	// OnTagged is called when each tagging operation is done.
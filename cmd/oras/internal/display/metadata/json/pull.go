@@ -32,6 +32,16 @@ type PullHandler struct {
 	out    io.Writer
 }
 
+// OnPlatformPulled implements metadata.PullHandler.
+func (ph *PullHandler) OnPlatformPulled(platform ocispec.Platform, outputDir string, desc ocispec.Descriptor, descPath string) error {
+	return ph.pulled.AddPlatform(platform, outputDir, desc, descPath)
+}
+
+// OnFileRemoved implements metadata.PullHandler.
+func (ph *PullHandler) OnFileRemoved(path string) error {
+	return ph.pulled.AddRemoved(path)
+}
+
 // OnLayerSkipped implements metadata.PullHandler.
 func (ph *PullHandler) OnLayerSkipped(ocispec.Descriptor) error {
 	return nil
@@ -52,5 +62,5 @@ func (ph *PullHandler) OnFilePulled(name string, outputDir string, desc ocispec.
 
 // OnCompleted implements metadata.PullHandler.
 func (ph *PullHandler) OnCompleted(opts *option.Target, desc ocispec.Descriptor) error {
-	return output.PrintPrettyJSON(ph.out, model.NewPull(ph.path+"@"+desc.Digest.String(), ph.pulled.Files()))
+	return output.PrintPrettyJSON(ph.out, model.NewPull(ph.path+"@"+desc.Digest.String(), &ph.pulled))
 }
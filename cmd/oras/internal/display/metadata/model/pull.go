@@ -54,25 +54,39 @@ func newFile(name string, outputDir string, desc ocispec.Descriptor, descPath st
 	}, nil
 }
 
+// Platform records metadata of a platform pulled from an index.
+type Platform struct {
+	// Path is the absolute path of the directory the platform is pulled into.
+	Path     string           `json:"path"`
+	Platform ocispec.Platform `json:"platform"`
+	Descriptor
+}
+
 type pull struct {
 	DigestReference
-	Files []File `json:"files"`
+	Files     []File     `json:"files"`
+	Platforms []Platform `json:"platforms,omitempty"`
+	Removed   []string   `json:"removed,omitempty"`
 }
 
 // NewPull creates a new metadata struct for pull command.
-func NewPull(digestReference string, files []File) any {
+func NewPull(digestReference string, pulled *Pulled) any {
 	return pull{
 		DigestReference: DigestReference{
 			Reference: digestReference,
 		},
-		Files: files,
+		Files:     pulled.Files(),
+		Platforms: pulled.Platforms(),
+		Removed:   pulled.Removed(),
 	}
 }
 
-// Pulled records all pulled files.
+// Pulled records all pulled files and platforms, and the stale files removed.
 type Pulled struct {
-	lock  sync.Mutex
-	files []File
+	lock      sync.Mutex
+	files     []File
+	platforms []Platform
+	removed   []string
 }
 
 // Files returns all pulled files.
@@ -82,6 +96,29 @@ func (p *Pulled) Files() []File {
 	return slices.Clone(p.files)
 }
 
+// Platforms returns all pulled platforms.
+func (p *Pulled) Platforms() []Platform {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return slices.Clone(p.platforms)
+}
+
+// AddPlatform adds a pulled platform.
+func (p *Pulled) AddPlatform(platform ocispec.Platform, outputDir string, desc ocispec.Descriptor, descPath string) error {
+	path, err := filepath.Abs(outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of %s: %w", outputDir, err)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.platforms = append(p.platforms, Platform{
+		Path:       path,
+		Platform:   platform,
+		Descriptor: FromDescriptor(descPath, desc),
+	})
+	return nil
+}
+
 // Add adds a pulled file.
 func (p *Pulled) Add(name string, outputDir string, desc ocispec.Descriptor, descPath string) error {
 	p.lock.Lock()
@@ -93,3 +130,22 @@ func (p *Pulled) Add(name string, outputDir string, desc ocispec.Descriptor, des
 	p.files = append(p.files, file)
 	return nil
 }
+
+// Removed returns the absolute paths of all removed files.
+func (p *Pulled) Removed() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return slices.Clone(p.removed)
+}
+
+// AddRemoved adds a removed file.
+func (p *Pulled) AddRemoved(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of removed file %s: %w", path, err)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.removed = append(p.removed, path)
+	return nil
+}
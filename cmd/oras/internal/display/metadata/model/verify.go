@@ -0,0 +1,83 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+
+	ofile "oras.land/oras/internal/file"
+)
+
+type verify struct {
+	DigestReference
+	Path     string   `json:"path"`
+	Missing  []string `json:"missing"`
+	Modified []string `json:"modified"`
+	Extra    []string `json:"extra"`
+}
+
+// NewVerify creates a new metadata struct for verify command.
+func NewVerify(digestReference string, dir string, verified *Verified) (any, error) {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", dir, err)
+	}
+	return verify{
+		DigestReference: DigestReference{
+			Reference: digestReference,
+		},
+		Path:     path,
+		Missing:  verified.missing,
+		Modified: verified.modified,
+		Extra:    verified.extra,
+	}, nil
+}
+
+// Verified records the files differing from the verified artifact.
+type Verified struct {
+	missing  []string
+	modified []string
+	extra    []string
+}
+
+// NewVerified creates a record with no differences.
+func NewVerified() *Verified {
+	return &Verified{
+		missing:  []string{},
+		modified: []string{},
+		extra:    []string{},
+	}
+}
+
+// Add adds a file of the difference status.
+func (v *Verified) Add(path string, status string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of %s: %w", path, err)
+	}
+	switch status {
+	case ofile.StatusMissing:
+		v.missing = append(v.missing, path)
+	case ofile.StatusModified:
+		v.modified = append(v.modified, path)
+	case ofile.StatusExtra:
+		v.extra = append(v.extra, path)
+	default:
+		return fmt.Errorf("unknown difference status %q of %s", status, path)
+	}
+	return nil
+}
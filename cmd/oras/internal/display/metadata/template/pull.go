@@ -35,7 +35,7 @@ type PullHandler struct {
 
 // OnCompleted implements metadata.PullHandler.
 func (ph *PullHandler) OnCompleted(opts *option.Target, desc ocispec.Descriptor) error {
-	return output.ParseAndWrite(ph.out, model.NewPull(ph.path+"@"+desc.Digest.String(), ph.pulled.Files()), ph.template)
+	return output.ParseAndWrite(ph.out, model.NewPull(ph.path+"@"+desc.Digest.String(), &ph.pulled), ph.template)
 }
 
 // OnFilePulled implements metadata.PullHandler.
@@ -43,6 +43,16 @@ func (ph *PullHandler) OnFilePulled(name string, outputDir string, desc ocispec.
 	return ph.pulled.Add(name, outputDir, desc, descPath)
 }
 
+// OnPlatformPulled implements metadata.PullHandler.
+func (ph *PullHandler) OnPlatformPulled(platform ocispec.Platform, outputDir string, desc ocispec.Descriptor, descPath string) error {
+	return ph.pulled.AddPlatform(platform, outputDir, desc, descPath)
+}
+
+// OnFileRemoved implements metadata.PullHandler.
+func (ph *PullHandler) OnFileRemoved(path string) error {
+	return ph.pulled.AddRemoved(path)
+}
+
 // OnLayerSkipped implements metadata.PullHandler.
 func (ph *PullHandler) OnLayerSkipped(ocispec.Descriptor) error {
 	return nil
@@ -0,0 +1,58 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/cmd/oras/internal/display/metadata"
+	"oras.land/oras/cmd/oras/internal/display/metadata/model"
+	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/cmd/oras/internal/output"
+)
+
+// VerifyHandler handles go-template metadata output for verify events.
+type VerifyHandler struct {
+	template string
+	path     string
+	verified *model.Verified
+	out      io.Writer
+}
+
+// NewVerifyHandler returns a new handler for verify events.
+func NewVerifyHandler(out io.Writer, path string, template string) metadata.VerifyHandler {
+	return &VerifyHandler{
+		template: template,
+		path:     path,
+		verified: model.NewVerified(),
+		out:      out,
+	}
+}
+
+// OnDifference implements metadata.VerifyHandler.
+func (vh *VerifyHandler) OnDifference(path string, status string) error {
+	return vh.verified.Add(path, status)
+}
+
+// OnCompleted implements metadata.VerifyHandler.
+func (vh *VerifyHandler) OnCompleted(_ *option.Target, desc ocispec.Descriptor, dir string) error {
+	verified, err := model.NewVerify(vh.path+"@"+desc.Digest.String(), dir, vh.verified)
+	if err != nil {
+		return err
+	}
+	return output.ParseAndWrite(vh.out, verified, vh.template)
+}
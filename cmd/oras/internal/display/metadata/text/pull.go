@@ -46,6 +46,20 @@ func (ph *PullHandler) OnFilePulled(_ string, _ string, _ ocispec.Descriptor, _
 	return nil
 }
 
+// OnPlatformPulled implements metadata.PullHandler.
+func (ph *PullHandler) OnPlatformPulled(platform ocispec.Platform, outputDir string, _ ocispec.Descriptor, _ string) error {
+	name := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		name += "/" + platform.Variant
+	}
+	return ph.printer.Printf("Pulled platform %s into %s\n", name, outputDir)
+}
+
+// OnFileRemoved implements metadata.PullHandler.
+func (ph *PullHandler) OnFileRemoved(path string) error {
+	return ph.printer.Println("Removed", path)
+}
+
 // OnLayerSkipped implements metadata.PullHandler.
 func (ph *PullHandler) OnLayerSkipped(ocispec.Descriptor) error {
 	ph.layerSkipped.Store(true)
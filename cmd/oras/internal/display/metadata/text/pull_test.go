@@ -0,0 +1,37 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/cmd/oras/internal/output"
+)
+
+func TestPullHandler_OnPlatformPulled(t *testing.T) {
+	out := &bytes.Buffer{}
+	ph := NewPullHandler(output.NewPrinter(out, os.Stderr))
+	platform := ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+	if err := ph.OnPlatformPulled(platform, "out/linux_arm64_v8", ocispec.Descriptor{}, "localhost:5000/test"); err != nil {
+		t.Fatal("PullHandler.OnPlatformPulled() error =", err)
+	}
+	if got, want := out.String(), "Pulled platform linux/arm64/v8 into out/linux_arm64_v8\n"; got != want {
+		t.Errorf("PullHandler.OnPlatformPulled() printed %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text
+
+import (
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/cmd/oras/internal/display/metadata"
+	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/cmd/oras/internal/output"
+	ofile "oras.land/oras/internal/file"
+)
+
+// differencePrompts are the prompts of the difference statuses.
+var differencePrompts = map[string]string{
+	ofile.StatusMissing:  "Missing ",
+	ofile.StatusModified: "Modified",
+	ofile.StatusExtra:    "Extra   ",
+}
+
+// VerifyHandler handles text metadata output for verify events.
+type VerifyHandler struct {
+	printer     *output.Printer
+	differences int
+}
+
+// NewVerifyHandler returns a new handler for verify events.
+func NewVerifyHandler(printer *output.Printer) metadata.VerifyHandler {
+	return &VerifyHandler{
+		printer: printer,
+	}
+}
+
+// OnDifference implements metadata.VerifyHandler.
+func (vh *VerifyHandler) OnDifference(path string, status string) error {
+	prompt, ok := differencePrompts[status]
+	if !ok {
+		return fmt.Errorf("unknown difference status %q of %s", status, path)
+	}
+	vh.differences++
+	return vh.printer.Println(prompt, path)
+}
+
+// OnCompleted implements metadata.VerifyHandler.
+func (vh *VerifyHandler) OnCompleted(opts *option.Target, desc ocispec.Descriptor, dir string) error {
+	if vh.differences > 0 {
+		return nil
+	}
+	_ = vh.printer.Printf("Verified %s against %s\n", dir, opts.AnnotatedReference())
+	return vh.printer.Println("Digest:", desc.Digest)
+}
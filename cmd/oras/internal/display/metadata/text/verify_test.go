@@ -0,0 +1,47 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/cmd/oras/internal/output"
+	ofile "oras.land/oras/internal/file"
+)
+
+func TestVerifyHandler(t *testing.T) {
+	out := &bytes.Buffer{}
+	vh := NewVerifyHandler(output.NewPrinter(out, os.Stderr))
+	for _, status := range []string{ofile.StatusMissing, ofile.StatusModified, ofile.StatusExtra} {
+		if err := vh.OnDifference("out/"+status, status); err != nil {
+			t.Fatal("VerifyHandler.OnDifference() error =", err)
+		}
+	}
+	if err := vh.OnDifference("out/foo", "unknown"); err == nil {
+		t.Error("VerifyHandler.OnDifference() error = nil, want error for unknown status")
+	}
+	if err := vh.OnCompleted(&option.Target{}, ocispec.Descriptor{}, "out"); err != nil {
+		t.Fatal("VerifyHandler.OnCompleted() error =", err)
+	}
+	want := "Missing  out/missing\nModified out/modified\nExtra    out/extra\n"
+	if got := out.String(); got != want {
+		t.Errorf("VerifyHandler printed %q, want %q", got, want)
+	}
+}
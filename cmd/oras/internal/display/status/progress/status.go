@@ -25,6 +25,7 @@ import (
 	"github.com/morikuni/aec"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras/cmd/oras/internal/display/status/progress/humanize"
+	"oras.land/oras/internal/descriptor"
 )
 
 const (
@@ -71,10 +72,7 @@ func (s *status) String(width int) (string, string) {
 	// todo: doesn't support multiline prompt
 	total := uint64(s.descriptor.Size)
 
-	name := s.descriptor.Annotations["org.opencontainers.image.title"]
-	if name == "" {
-		name = s.descriptor.MediaType
-	}
+	name, _ := descriptor.GetTitleOrMediaType(s.descriptor)
 
 	// format:  [left--------------------------------------------][margin][right---------------------------------]
 	//          mark(1) bar(22) speed(8) action(<=11) name(<=126)        size_per_size(<=13) percent(8) time(>=6)
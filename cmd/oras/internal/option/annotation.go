@@ -16,13 +16,34 @@ limitations under the License.
 package option
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	oerrors "oras.land/oras/cmd/oras/internal/errors"
+	"oras.land/oras/internal/buildenv"
+)
+
+// Annotation presets filling the standard manifest annotations from the build
+// environment.
+const (
+	// AnnotationPresetCreated fills the creation time from the clock, or from
+	// SOURCE_DATE_EPOCH if set.
+	AnnotationPresetCreated = "created"
+	// AnnotationPresetGit fills the revision, the source and the version from
+	// the git repository of the working directory.
+	AnnotationPresetGit = "git"
+	// AnnotationPresetCI fills the revision, the source and the version from
+	// the environment variables of CI systems.
+	AnnotationPresetCI = "ci"
+	// AnnotationPresetAll applies all the presets.
+	AnnotationPresetAll = "all"
 )
 
 var (
@@ -34,6 +55,9 @@ var (
 type Annotation struct {
 	// ManifestAnnotations contains raw input of manifest annotation "key=value" pairs
 	ManifestAnnotations []string
+	// AnnotationPresets contains the presets filling standard manifest
+	// annotations
+	AnnotationPresets []string
 
 	// Annotations contains parsed manifest and config annotations
 	Annotations map[string]map[string]string
@@ -42,10 +66,17 @@ type Annotation struct {
 // ApplyFlags applies flags to a command flag set.
 func (opts *Annotation) ApplyFlags(fs *pflag.FlagSet) {
 	fs.StringArrayVarP(&opts.ManifestAnnotations, "annotation", "a", nil, "manifest annotations")
+	fs.StringSliceVarP(&opts.AnnotationPresets, "annotation-preset", "", nil, "[Experimental] fill standard manifest annotations from the build environment, options: created, git, ci, all. Explicit annotations take precedence")
 }
 
 // Parse parses the input annotation flags.
-func (opts *Annotation) Parse(*cobra.Command) error {
+func (opts *Annotation) Parse(cmd *cobra.Command) error {
+	return opts.parse(cmd.Context())
+}
+
+// parse parses the input annotation flags, with the presets looked up within
+// ctx.
+func (opts *Annotation) parse(ctx context.Context) error {
 	manifestAnnotations := make(map[string]string)
 	for _, anno := range opts.ManifestAnnotations {
 		key, val, success := strings.Cut(anno, "=")
@@ -63,5 +94,74 @@ func (opts *Annotation) Parse(*cobra.Command) error {
 	opts.Annotations = map[string]map[string]string{
 		AnnotationManifest: manifestAnnotations,
 	}
+	return opts.applyPresets(ctx)
+}
+
+// applyPresets fills the manifest annotations not explicitly specified from
+// the presets. Later presets take precedence in the order of created, git and
+// ci.
+func (opts *Annotation) applyPresets(ctx context.Context) error {
+	if len(opts.AnnotationPresets) == 0 {
+		return nil
+	}
+	presets := make(map[string]bool)
+	for _, preset := range opts.AnnotationPresets {
+		switch preset {
+		case AnnotationPresetCreated, AnnotationPresetGit, AnnotationPresetCI:
+			presets[preset] = true
+		case AnnotationPresetAll:
+			presets[AnnotationPresetCreated] = true
+			presets[AnnotationPresetGit] = true
+			presets[AnnotationPresetCI] = true
+		default:
+			return &oerrors.Error{
+				Err:            fmt.Errorf("unknown annotation preset %q", preset),
+				Recommendation: fmt.Sprintf("Available presets are %s", strings.Join([]string{AnnotationPresetCreated, AnnotationPresetGit, AnnotationPresetCI, AnnotationPresetAll}, ", ")),
+			}
+		}
+	}
+
+	filled := make(map[string]string)
+	if presets[AnnotationPresetCreated] {
+		created, err := sourceDateEpoch()
+		if err != nil {
+			return err
+		}
+		if created.IsZero() {
+			created = time.Now().UTC()
+		}
+		filled[ocispec.AnnotationCreated] = created.Format(time.RFC3339)
+	}
+	if presets[AnnotationPresetGit] {
+		annotations, err := buildenv.FromGit(ctx, ".")
+		if err != nil {
+			return &oerrors.Error{
+				Err:            err,
+				Recommendation: fmt.Sprintf("Run the command in a git repository with at least one commit, or remove %q from --annotation-preset", AnnotationPresetGit),
+			}
+		}
+		for k, v := range annotations {
+			filled[k] = v
+		}
+	}
+	if presets[AnnotationPresetCI] {
+		for k, v := range buildenv.FromCI(os.Getenv) {
+			filled[k] = v
+		}
+	}
+
+	if opts.Annotations == nil {
+		opts.Annotations = make(map[string]map[string]string)
+	}
+	manifestAnnotations := opts.Annotations[AnnotationManifest]
+	if manifestAnnotations == nil {
+		manifestAnnotations = make(map[string]string)
+		opts.Annotations[AnnotationManifest] = manifestAnnotations
+	}
+	for k, v := range filled {
+		if _, ok := manifestAnnotations[k]; !ok {
+			manifestAnnotations[k] = v
+		}
+	}
 	return nil
 }
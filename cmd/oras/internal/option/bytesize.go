@@ -0,0 +1,93 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package option
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps lower-cased size suffixes to their multipliers.
+// SI suffixes are decimal and IEC suffixes are binary. Single letter suffixes
+// are treated as binary units.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1e3,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1e6,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1e9,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1e12,
+	"tib": 1 << 40,
+}
+
+// ByteSize is a flag value representing a size in bytes. It accepts plain
+// numbers as well as numbers with units, e.g. 512MiB, 2GB or 1.5g.
+// ByteSize implements pflag.Value.
+type ByteSize int64
+
+// ParseByteSize parses a human readable size into the number of bytes.
+func ParseByteSize(s string) (int64, error) {
+	raw := strings.TrimSpace(s)
+	i := strings.IndexFunc(raw, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(raw)
+	}
+	number, unit := raw[:i], strings.ToLower(strings.TrimSpace(raw[i:]))
+	multiplier, ok := byteUnits[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid size %q: expecting a number with an optional unit such as B, KB, KiB, MB, MiB, GB or GiB", s)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	size := value * multiplier
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: value out of range", s)
+	}
+	return int64(size), nil
+}
+
+// Set implements pflag.Value.
+func (b *ByteSize) Set(s string) error {
+	size, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(size)
+	return nil
+}
+
+// String implements pflag.Value.
+func (b *ByteSize) String() string {
+	return strconv.FormatInt(int64(*b), 10)
+}
+
+// Type implements pflag.Value.
+func (b *ByteSize) Type() string {
+	return "size"
+}
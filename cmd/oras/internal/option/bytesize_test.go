@@ -0,0 +1,71 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package option
+
+import (
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "1024", want: 1024},
+		{input: "10B", want: 10},
+		{input: "1k", want: 1 << 10},
+		{input: "2KB", want: 2000},
+		{input: "2KiB", want: 2 << 10},
+		{input: "512MiB", want: 512 << 20},
+		{input: "1.5g", want: 3 << 29},
+		{input: "2 GiB", want: 2 << 30},
+		{input: "1TB", want: 1e12},
+		{input: "", wantErr: true},
+		{input: "GiB", wantErr: true},
+		{input: "12XB", wantErr: true},
+		{input: "1.2.3MB", wantErr: true},
+		{input: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteSize_Set(t *testing.T) {
+	var size ByteSize
+	if err := size.Set("4MiB"); err != nil {
+		t.Fatal("ByteSize.Set() error =", err)
+	}
+	if want := ByteSize(4 << 20); size != want {
+		t.Errorf("ByteSize.Set() = %v, want %v", size, want)
+	}
+	if got, want := size.String(), "4194304"; got != want {
+		t.Errorf("ByteSize.String() = %v, want %v", got, want)
+	}
+	if err := size.Set("invalid"); err == nil {
+		t.Error("ByteSize.Set() error = nil, want error")
+	}
+}
@@ -16,26 +16,198 @@ limitations under the License.
 package option
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"time"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"oras.land/oras-go/v2"
-	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	oerrors "oras.land/oras/cmd/oras/internal/errors"
 	"oras.land/oras/internal/cache"
 )
 
+const (
+	cacheRootEnv    = "ORAS_CACHE"
+	cacheMaxSizeEnv = "ORAS_CACHE_MAX_SIZE"
+)
+
+const (
+	offlineFlag       = "offline"
+	cacheTTLFlag      = "cache-ttl"
+	populateCacheFlag = "populate-cache"
+)
+
+// Cache option struct.
 type Cache struct {
-	Root string
+	Root          string
+	Offline       bool
+	TTL           time.Duration
+	PopulateCache bool
+
+	applyResolveFlags  bool
+	applyPopulateFlags bool
+}
+
+// EnableResolveFlags enables flags controlling how tags are resolved via the
+// cache.
+func (opts *Cache) EnableResolveFlags() {
+	opts.applyResolveFlags = true
+}
+
+// EnablePopulateFlag enables the flag for storing written content in the
+// cache.
+func (opts *Cache) EnablePopulateFlag() {
+	opts.applyPopulateFlags = true
+}
+
+// ApplyFlags applies flags to a command flag set.
+func (opts *Cache) ApplyFlags(fs *pflag.FlagSet) {
+	if opts.applyPopulateFlags {
+		fs.BoolVar(&opts.PopulateCache, populateCacheFlag, false, "[Experimental] also store the written content and tags in the local cache for later reads, requires "+cacheRootEnv)
+	}
+	if !opts.applyResolveFlags {
+		return
+	}
+	fs.BoolVar(&opts.Offline, offlineFlag, false, "[Experimental] resolve references and fetch content from the local cache only, requires "+cacheRootEnv)
+	fs.DurationVar(&opts.TTL, cacheTTLFlag, 0, "[Experimental] resolve tags from the local cache if cached within the duration, or if the registry is unreachable, requires "+cacheRootEnv)
+}
+
+// Parse parses the cache options.
+func (opts *Cache) Parse(cmd *cobra.Command) error {
+	if opts.PopulateCache && os.Getenv(cacheRootEnv) == "" {
+		return &oerrors.Error{
+			Err:            fmt.Errorf("--%s requires a local cache", populateCacheFlag),
+			Recommendation: fmt.Sprintf("Set the environment variable %s to the cache directory", cacheRootEnv),
+		}
+	}
+	if !opts.applyResolveFlags {
+		return nil
+	}
+	if err := oerrors.CheckMutuallyExclusiveFlags(cmd.Flags(), offlineFlag, cacheTTLFlag); err != nil {
+		return err
+	}
+	if opts.TTL < 0 {
+		return fmt.Errorf("invalid --%s %v: duration must not be negative", cacheTTLFlag, opts.TTL)
+	}
+	if (opts.Offline || opts.TTL > 0) && os.Getenv(cacheRootEnv) == "" {
+		flag := "--" + offlineFlag
+		if !opts.Offline {
+			flag = "--" + cacheTTLFlag
+		}
+		return &oerrors.Error{
+			Err:            fmt.Errorf("%s requires a local cache", flag),
+			Recommendation: fmt.Sprintf("Set the environment variable %s to the cache directory", cacheRootEnv),
+		}
+	}
+	return nil
 }
 
 // CachedTarget gets the target storage with caching if cache root is specified.
 func (opts *Cache) CachedTarget(src oras.ReadOnlyTarget) (oras.ReadOnlyTarget, error) {
-	opts.Root = os.Getenv("ORAS_CACHE")
+	opts.Root = os.Getenv(cacheRootEnv)
 	if opts.Root != "" {
-		ociStore, err := oci.New(opts.Root)
+		store, err := opts.newStore()
 		if err != nil {
 			return nil, err
 		}
-		return cache.New(src, ociStore), nil
+		return cache.NewWithResolveOptions(src, store, opts.resolveOptions(src)), nil
 	}
 	return src, nil
 }
+
+// CachedGraphTarget gets the graph target storage with caching if cache root
+// is specified.
+func (opts *Cache) CachedGraphTarget(src oras.ReadOnlyGraphTarget) (oras.ReadOnlyGraphTarget, error) {
+	opts.Root = os.Getenv(cacheRootEnv)
+	if opts.Root != "" {
+		store, err := opts.newStore()
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewGraph(src, store, opts.resolveOptions(src)), nil
+	}
+	return src, nil
+}
+
+// Populate stores the graph rooted at root from src into the cache if
+// --populate-cache is used. tags are recorded as tags of root in dst.
+func (opts *Cache) Populate(ctx context.Context, src content.ReadOnlyStorage, dst oras.ReadOnlyTarget, root ocispec.Descriptor, tags []string, copyOpts oras.CopyGraphOptions) error {
+	if !opts.PopulateCache {
+		return nil
+	}
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+	if err := cache.Populate(ctx, store, src, root, cache.PopulateOptions{
+		CopyGraphOptions: copyOpts,
+		Namespace:        opts.resolveOptions(dst).Namespace,
+		Tags:             tags,
+	}); err != nil {
+		return fmt.Errorf("failed to populate the local cache: %w", err)
+	}
+	return nil
+}
+
+// RecordTags records tags of root in dst into the cache if --populate-cache
+// is used, for the content of root cached while being read from the source.
+func (opts *Cache) RecordTags(ctx context.Context, dst oras.ReadOnlyTarget, root ocispec.Descriptor, tags []string) error {
+	if !opts.PopulateCache {
+		return nil
+	}
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+	if err := cache.RecordTags(ctx, store, root, opts.resolveOptions(dst).Namespace, tags); err != nil {
+		return fmt.Errorf("failed to populate the local cache: %w", err)
+	}
+	return nil
+}
+
+// resolveOptions returns the options for resolving references of src via the
+// cache.
+func (opts *Cache) resolveOptions(src oras.ReadOnlyTarget) cache.ResolveOptions {
+	resolveOpts := cache.ResolveOptions{
+		Offline: opts.Offline,
+		TTL:     opts.TTL,
+	}
+	if repo, ok := src.(*remote.Repository); ok {
+		resolveOpts.Namespace = repo.Reference.Registry + "/" + repo.Reference.Repository
+	}
+	return resolveOpts
+}
+
+// NewStore returns the cache store located at the cache root.
+func (opts *Cache) NewStore() (*cache.Store, error) {
+	opts.Root = os.Getenv(cacheRootEnv)
+	if opts.Root == "" {
+		return nil, &oerrors.Error{
+			Err:            errors.New("cache root is not specified"),
+			Recommendation: fmt.Sprintf("Set the environment variable %s to the cache directory", cacheRootEnv),
+		}
+	}
+	return opts.newStore()
+}
+
+// newStore creates the cache store with the size limit applied if
+// ORAS_CACHE_MAX_SIZE is specified.
+func (opts *Cache) newStore() (*cache.Store, error) {
+	store, err := cache.NewStore(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize := os.Getenv(cacheMaxSizeEnv); maxSize != "" {
+		store.MaxSize, err = ParseByteSize(maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", cacheMaxSizeEnv, err)
+		}
+	}
+	return store, nil
+}
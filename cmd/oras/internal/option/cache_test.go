@@ -20,9 +20,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/memory"
-	"oras.land/oras-go/v2/content/oci"
 	"oras.land/oras/internal/cache"
 )
 
@@ -34,11 +34,11 @@ func TestCache_CachedTarget(t *testing.T) {
 	defer os.Unsetenv("ORAS_CACHE")
 	opts := Cache{}
 
-	ociStore, err := oci.New(tempDir)
+	store, err := cache.NewStore(tempDir)
 	if err != nil {
-		t.Fatal("error calling oci.New(), error =", err)
+		t.Fatal("error calling cache.NewStore(), error =", err)
 	}
-	want := cache.New(mockTarget, ociStore)
+	want := cache.New(mockTarget, store)
 
 	got, err := opts.CachedTarget(mockTarget)
 	if err != nil {
@@ -61,3 +61,83 @@ func TestCache_CachedTarget_emptyRoot(t *testing.T) {
 		t.Fatalf("Cache.CachedTarget() got %v, want %v", got, mockTarget)
 	}
 }
+
+func TestCache_CachedTarget_maxSize(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("ORAS_CACHE", tempDir)
+	t.Setenv("ORAS_CACHE_MAX_SIZE", "1MiB")
+	opts := Cache{}
+
+	store, err := opts.NewStore()
+	if err != nil {
+		t.Fatal("Cache.NewStore() error=", err)
+	}
+	if want := int64(1 << 20); store.MaxSize != want {
+		t.Fatalf("Cache.NewStore() MaxSize = %v, want %v", store.MaxSize, want)
+	}
+
+	t.Setenv("ORAS_CACHE_MAX_SIZE", "invalid")
+	if _, err := opts.CachedTarget(mockTarget); err == nil {
+		t.Fatal("Cache.CachedTarget() error = nil, want error")
+	}
+}
+
+func TestCache_NewStore_emptyRoot(t *testing.T) {
+	t.Setenv("ORAS_CACHE", "")
+	opts := Cache{}
+
+	if _, err := opts.NewStore(); err == nil {
+		t.Fatal("Cache.NewStore() error = nil, want error")
+	}
+}
+
+func TestCache_Parse_resolveFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		root    string
+		wantErr bool
+	}{
+		{name: "no flags", args: nil},
+		{name: "offline", args: []string{"--offline"}, root: "cache"},
+		{name: "cache ttl", args: []string{"--cache-ttl", "1h"}, root: "cache"},
+		{name: "offline without cache", args: []string{"--offline"}, wantErr: true},
+		{name: "cache ttl without cache", args: []string{"--cache-ttl", "1h"}, wantErr: true},
+		{name: "negative cache ttl", args: []string{"--cache-ttl", "-1h"}, root: "cache", wantErr: true},
+		{name: "mutually exclusive", args: []string{"--offline", "--cache-ttl", "1h"}, root: "cache", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ORAS_CACHE", tt.root)
+			var opts Cache
+			opts.EnableResolveFlags()
+			cmd := &cobra.Command{}
+			opts.ApplyFlags(cmd.Flags())
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal("cmd.ParseFlags() error =", err)
+			}
+			if err := opts.Parse(cmd); (err != nil) != tt.wantErr {
+				t.Errorf("Cache.Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCache_Parse_populateFlag(t *testing.T) {
+	var opts Cache
+	opts.EnablePopulateFlag()
+	cmd := &cobra.Command{}
+	opts.ApplyFlags(cmd.Flags())
+	if err := cmd.ParseFlags([]string{"--populate-cache"}); err != nil {
+		t.Fatal("cmd.ParseFlags() error =", err)
+	}
+
+	t.Setenv("ORAS_CACHE", "")
+	if err := opts.Parse(cmd); err == nil {
+		t.Error("Cache.Parse() error = nil, want error without cache root")
+	}
+	t.Setenv("ORAS_CACHE", t.TempDir())
+	if err := opts.Parse(cmd); err != nil {
+		t.Error("Cache.Parse() error =", err)
+	}
+}
@@ -20,16 +20,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
 	"oras.land/oras-go/v2/content"
 	oerrors "oras.land/oras/cmd/oras/internal/errors"
 	"oras.land/oras/cmd/oras/internal/fileref"
+	"oras.land/oras/internal/archive"
+	"oras.land/oras/internal/file"
+	"oras.land/oras/internal/mediatype"
 )
 
 // Pre-defined annotation keys for annotation file
@@ -38,6 +45,11 @@ const (
 	AnnotationConfig   = "$config"
 )
 
+// EnvSourceDateEpoch is the environment variable specifying the timestamp of
+// reproducible builds.
+// Reference: https://reproducible-builds.org/specs/source-date-epoch/
+const EnvSourceDateEpoch = "SOURCE_DATE_EPOCH"
+
 var (
 	errAnnotationConflict = errors.New("`--annotation` and `--annotation-file` cannot be both specified")
 	errPathValidation     = errors.New("absolute file path detected. If it's intentional, use --disable-path-validation flag to skip this check")
@@ -50,6 +62,18 @@ type Packer struct {
 	ManifestExportPath     string
 	PathValidationDisabled bool
 	AnnotationFilePath     string
+	Reproducible           bool
+	Compression            string
+	CompressionLevel       int
+	// SourceDateEpoch is the timestamp read from SOURCE_DATE_EPOCH, zero if
+	// not set.
+	SourceDateEpoch time.Time
+	Excludes        []string
+	FilesFrom       string
+	InferMediaType  bool
+	MediaTypeMap    string
+	// MediaTypeMapping is the mapping loaded from MediaTypeMap.
+	MediaTypeMapping map[string]string
 
 	FileRefs []string
 }
@@ -61,6 +85,45 @@ func (opts *Packer) ApplyFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&opts.ManifestExportPath, "export-manifest", "", "", "`path` of the pushed manifest")
 	fs.StringVarP(&opts.AnnotationFilePath, "annotation-file", "", "", "path of the annotation file")
 	fs.BoolVarP(&opts.PathValidationDisabled, "disable-path-validation", "", false, "skip path validation")
+	fs.StringArrayVarP(&opts.Excludes, "exclude", "", nil, "[Experimental] exclude files matching the glob `pattern` when expanding file patterns, in addition to the patterns listed in "+file.IgnoreFileName)
+	fs.StringVarP(&opts.FilesFrom, "files-from", "", "", "[Experimental] read newline or NUL separated <file>[:<type>] entries from `path`, use - for stdin")
+	fs.BoolVarP(&opts.InferMediaType, "infer-media-type", "", false, "[Experimental] infer the media types of files without explicit types from their extensions and content")
+	fs.StringVarP(&opts.MediaTypeMap, "media-type-map", "", "", "[Experimental] `path` of the YAML or JSON file mapping file extensions or glob patterns to media types, implies --infer-media-type")
+	fs.StringVarP(&opts.Compression, "compression", "", string(archive.CompressionGzip), "[Experimental] compression algorithm of directories, options: gzip, zstd, none")
+	fs.IntVarP(&opts.CompressionLevel, "compression-level", "", 0, "[Experimental] compression level of directories, 0 for the default level of the compression algorithm")
+	fs.BoolVarP(&opts.Reproducible, "reproducible", "", false, "[Experimental] pack directories reproducibly by sorting entries, normalizing ownership and permissions, and setting timestamps to $"+EnvSourceDateEpoch+" (Unix epoch if not set)")
+}
+
+// NewMediaTypeInferrer returns an inferrer for the media types of files if
+// inference is requested, otherwise nil.
+func (opts *Packer) NewMediaTypeInferrer() *mediatype.Inferrer {
+	if !opts.InferMediaType {
+		return nil
+	}
+	return &mediatype.Inferrer{Mapping: opts.MediaTypeMapping}
+}
+
+// NewMetadataOptions returns the options for recording the metadata of files.
+func (opts *Packer) NewMetadataOptions() file.MetadataOptions {
+	return file.MetadataOptions{
+		Reproducible: opts.Reproducible,
+		ModTime:      opts.SourceDateEpoch,
+	}
+}
+
+// NewArchivePacker returns a packer for directories if reproducible packing or
+// non-default compression is requested, otherwise nil.
+func (opts *Packer) NewArchivePacker() *archive.Packer {
+	compression := archive.Compression(opts.Compression)
+	if !opts.Reproducible && compression == archive.CompressionGzip && opts.CompressionLevel == 0 {
+		return nil
+	}
+	return archive.NewPacker(archive.Options{
+		Reproducible:     opts.Reproducible,
+		ModTime:          opts.SourceDateEpoch,
+		Compression:      compression,
+		CompressionLevel: opts.CompressionLevel,
+	})
 }
 
 // ExportManifest saves the pushed manifest to a local file.
@@ -76,6 +139,9 @@ func (opts *Packer) ExportManifest(ctx context.Context, fetcher content.Fetcher,
 }
 
 func (opts *Packer) Parse(cmd *cobra.Command) error {
+	if err := opts.parseFileRefs(); err != nil {
+		return err
+	}
 	if !opts.PathValidationDisabled {
 		var failedPaths []string
 		for _, path := range opts.FileRefs {
@@ -92,11 +158,163 @@ func (opts *Packer) Parse(cmd *cobra.Command) error {
 			return fmt.Errorf("%w: %v", errPathValidation, strings.Join(failedPaths, ", "))
 		}
 	}
-	return opts.parseAnnotations(cmd)
+	if err := opts.parseMediaTypeMap(); err != nil {
+		return err
+	}
+	if err := opts.parseCompression(); err != nil {
+		return err
+	}
+	if err := opts.parseSourceDateEpoch(); err != nil {
+		return err
+	}
+	return opts.parseAnnotations(cmd.Context())
+}
+
+// parseFileRefs reads the file references from --files-from, expands the glob
+// patterns and removes the excluded files from the expansions. Files named
+// explicitly are kept even if excluded, and it fails if all files are excluded.
+func (opts *Packer) parseFileRefs() error {
+	refs := opts.FileRefs
+	if opts.FilesFrom != "" {
+		list, err := readFileList(opts.FilesFrom)
+		if err != nil {
+			return fmt.Errorf("failed to read file list from %s: %w", opts.FilesFrom, err)
+		}
+		refs = append(refs, list...)
+	}
+	patterns, err := file.ReadIgnoreFile(file.IgnoreFileName)
+	if err != nil {
+		return err
+	}
+	excluder, err := file.NewExcluder(append(patterns, opts.Excludes...))
+	if err != nil {
+		return err
+	}
+
+	var fileRefs []string
+	for _, ref := range refs {
+		path, _, err := fileref.Parse(ref, "")
+		if err != nil {
+			return err
+		}
+		if _, statErr := os.Lstat(path); statErr == nil || !file.IsPattern(path) {
+			fileRefs = append(fileRefs, ref)
+			continue
+		}
+		// keep the type suffix for the expanded files
+		suffix := ref[len(path):]
+		matches, err := file.Glob(path)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return &oerrors.Error{
+				Err:            fmt.Errorf("no files match the pattern %q", path),
+				Recommendation: "Please check the pattern and quote it to avoid expansion by the shell",
+			}
+		}
+		for _, match := range matches {
+			if !excluder.Excluded(match) {
+				fileRefs = append(fileRefs, match+suffix)
+			}
+		}
+	}
+	if len(refs) > 0 && len(fileRefs) == 0 {
+		return &oerrors.Error{
+			Err:            errors.New("all files matching the patterns are excluded"),
+			Recommendation: fmt.Sprintf("Please check the patterns passed to --exclude and listed in %s", file.IgnoreFileName),
+		}
+	}
+	opts.FileRefs = fileRefs
+	return nil
+}
+
+// readFileList reads the file list from path, or from stdin if path is "-".
+func readFileList(path string) ([]string, error) {
+	if path == "-" {
+		return file.ReadFileList(os.Stdin)
+	}
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return file.ReadFileList(fp)
+}
+
+// parseMediaTypeMap loads the mapping of media types.
+func (opts *Packer) parseMediaTypeMap() error {
+	if opts.MediaTypeMap == "" {
+		return nil
+	}
+	opts.InferMediaType = true
+	fp, err := os.Open(opts.MediaTypeMap)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	var mapping map[string]string
+	if err := yaml.NewDecoder(fp).Decode(&mapping); err != nil && !errors.Is(err, io.EOF) {
+		return &oerrors.Error{
+			Err:            fmt.Errorf("invalid media type map %s: %w", opts.MediaTypeMap, err),
+			Recommendation: `Please provide a YAML or JSON object mapping file extensions such as ".onnx" or glob patterns such as "models/**" to media types`,
+		}
+	}
+	if err := mediatype.ValidateMapping(mapping); err != nil {
+		return fmt.Errorf("invalid media type map %s: %w", opts.MediaTypeMap, err)
+	}
+	opts.MediaTypeMapping = mapping
+	return nil
+}
+
+// parseCompression validates the compression algorithm and level.
+func (opts *Packer) parseCompression() error {
+	compression, err := archive.ParseCompression(opts.Compression)
+	if err != nil {
+		return &oerrors.Error{
+			Err:            err,
+			Recommendation: `Please specify "--compression" with one of gzip, zstd or none`,
+		}
+	}
+	if err := compression.ValidateLevel(opts.CompressionLevel); err != nil {
+		return err
+	}
+	opts.Compression = string(compression)
+	return nil
+}
+
+// parseSourceDateEpoch loads the timestamp of reproducible builds.
+func (opts *Packer) parseSourceDateEpoch() error {
+	if !opts.Reproducible {
+		return nil
+	}
+	epoch, err := sourceDateEpoch()
+	if err != nil {
+		return err
+	}
+	opts.SourceDateEpoch = epoch
+	return nil
+}
+
+// sourceDateEpoch reads the timestamp from SOURCE_DATE_EPOCH, or returns zero
+// if not set.
+func sourceDateEpoch() (time.Time, error) {
+	value := os.Getenv(EnvSourceDateEpoch)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, &oerrors.Error{
+			Err:            fmt.Errorf("invalid %s %q: expecting a non-negative integer", EnvSourceDateEpoch, value),
+			Recommendation: fmt.Sprintf("Set %s to the number of seconds since the Unix epoch, e.g. the timestamp of the last commit", EnvSourceDateEpoch),
+		}
+	}
+	return time.Unix(seconds, 0).UTC(), nil
 }
 
 // parseAnnotations loads the manifest annotation map.
-func (opts *Packer) parseAnnotations(cmd *cobra.Command) error {
+func (opts *Packer) parseAnnotations(ctx context.Context) error {
 	if opts.AnnotationFilePath != "" && len(opts.ManifestAnnotations) != 0 {
 		return errAnnotationConflict
 	}
@@ -109,9 +327,9 @@ func (opts *Packer) parseAnnotations(cmd *cobra.Command) error {
 		}
 	}
 	if len(opts.ManifestAnnotations) != 0 {
-		return opts.Annotation.Parse(cmd)
+		return opts.Annotation.parse(ctx)
 	}
-	return nil
+	return opts.applyPresets(ctx)
 }
 
 // decodeJSON decodes a json file v to filename.
@@ -16,12 +16,14 @@ limitations under the License.
 package option
 
 import (
+	"context"
 	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -44,14 +46,14 @@ func TestPacker_parseAnnotations_err(t *testing.T) {
 		},
 		AnnotationFilePath: "this is not a file", // testFile,
 	}
-	if err := opts.parseAnnotations(nil); !errors.Is(err, errAnnotationConflict) {
+	if err := opts.parseAnnotations(context.Background()); !errors.Is(err, errAnnotationConflict) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	opts = Packer{
 		AnnotationFilePath: "this is not a file", // testFile,
 	}
-	if err := opts.parseAnnotations(nil); err == nil {
+	if err := opts.parseAnnotations(context.Background()); err == nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -60,7 +62,7 @@ func TestPacker_parseAnnotations_err(t *testing.T) {
 			ManifestAnnotations: []string{"KeyVal"},
 		},
 	}
-	if err := opts.parseAnnotations(nil); !errors.Is(err, errAnnotationFormat) {
+	if err := opts.parseAnnotations(context.Background()); !errors.Is(err, errAnnotationFormat) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -69,7 +71,7 @@ func TestPacker_parseAnnotations_err(t *testing.T) {
 			ManifestAnnotations: []string{"Key=Val1", "Key=Val2"},
 		},
 	}
-	if err := opts.parseAnnotations(nil); !errors.Is(err, errAnnotationDuplication) {
+	if err := opts.parseAnnotations(context.Background()); !errors.Is(err, errAnnotationDuplication) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -84,7 +86,7 @@ func TestPacker_parseAnnotations_annotationFile(t *testing.T) {
 		AnnotationFilePath: testFile,
 	}
 
-	err = opts.parseAnnotations(nil)
+	err = opts.parseAnnotations(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -103,7 +105,7 @@ func TestPacker_parseAnnotations_annotationFlag(t *testing.T) {
 			ManifestAnnotations: invalidFlag0,
 		},
 	}
-	err := opts.parseAnnotations(nil)
+	err := opts.parseAnnotations(context.Background())
 	if !errors.Is(err, errAnnotationFormat) {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -118,7 +120,7 @@ func TestPacker_parseAnnotations_annotationFlag(t *testing.T) {
 			ManifestAnnotations: invalidFlag1,
 		},
 	}
-	err = opts.parseAnnotations(nil)
+	err = opts.parseAnnotations(context.Background())
 	if !errors.Is(err, errAnnotationDuplication) {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -134,7 +136,7 @@ func TestPacker_parseAnnotations_annotationFlag(t *testing.T) {
 			ManifestAnnotations: validFlag,
 		},
 	}
-	err = opts.parseAnnotations(nil)
+	err = opts.parseAnnotations(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -152,3 +154,190 @@ func TestPacker_parseAnnotations_annotationFlag(t *testing.T) {
 		t.Fatalf("unexpected error: %v", errors.New("content not match"))
 	}
 }
+
+func TestPacker_parseAnnotations_annotationPreset(t *testing.T) {
+	t.Setenv(EnvSourceDateEpoch, "1700000000")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_SHA", "abc")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "oras-project/oras")
+	t.Setenv("GITHUB_REF_TYPE", "tag")
+	t.Setenv("GITHUB_REF_NAME", "v1.0.0")
+
+	opts := Packer{
+		Annotation: Annotation{
+			ManifestAnnotations: []string{"org.opencontainers.image.version=custom"},
+			AnnotationPresets:   []string{"created", "ci"},
+		},
+	}
+	if err := opts.parseAnnotations(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]map[string]string{
+		"$manifest": {
+			"org.opencontainers.image.created":  "2023-11-14T22:13:20Z",
+			"org.opencontainers.image.revision": "abc",
+			"org.opencontainers.image.source":   "https://github.com/oras-project/oras",
+			"org.opencontainers.image.version":  "custom",
+		},
+	}
+	if !reflect.DeepEqual(opts.Annotations, want) {
+		t.Fatalf("Packer.Annotations = %v, want %v", opts.Annotations, want)
+	}
+
+	opts = Packer{
+		Annotation: Annotation{
+			AnnotationPresets: []string{"unknown"},
+		},
+	}
+	if err := opts.parseAnnotations(context.Background()); err == nil {
+		t.Fatal("Packer.parseAnnotations() error = nil, want error for unknown preset")
+	}
+}
+
+func TestPacker_parseSourceDateEpoch(t *testing.T) {
+	tests := []struct {
+		name         string
+		reproducible bool
+		value        string
+		want         time.Time
+		wantErr      bool
+	}{
+		{name: "not reproducible", value: "1700000000"},
+		{name: "not set", reproducible: true},
+		{name: "valid", reproducible: true, value: "1700000000", want: time.Unix(1700000000, 0)},
+		{name: "invalid", reproducible: true, value: "yesterday", wantErr: true},
+		{name: "negative", reproducible: true, value: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvSourceDateEpoch, tt.value)
+			opts := Packer{Reproducible: tt.reproducible}
+			if err := opts.parseSourceDateEpoch(); (err != nil) != tt.wantErr {
+				t.Fatalf("Packer.parseSourceDateEpoch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !opts.SourceDateEpoch.Equal(tt.want) {
+				t.Errorf("Packer.SourceDateEpoch = %v, want %v", opts.SourceDateEpoch, tt.want)
+			}
+		})
+	}
+}
+
+func TestPacker_parseCompression(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression string
+		level       int
+		wantErr     bool
+	}{
+		{name: "default", compression: ""},
+		{name: "zstd", compression: "zstd", level: 19},
+		{name: "none", compression: "none"},
+		{name: "unsupported", compression: "bzip2", wantErr: true},
+		{name: "invalid level", compression: "gzip", level: 10, wantErr: true},
+		{name: "level without compression", compression: "none", level: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Packer{Compression: tt.compression, CompressionLevel: tt.level}
+			if err := opts.parseCompression(); (err != nil) != tt.wantErr {
+				t.Errorf("Packer.parseCompression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPacker_parseFileRefs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"models/a.onnx", "models/tmp/b.onnx", "models/c.onnx", "notes.txt", "list.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "list.txt"), []byte("notes.txt:text/plain\nmodels/tmp/*\n"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".orasignore"), []byte("models/c.onnx\n"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal("os.Getwd() error =", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal("os.Chdir() error =", err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	opts := Packer{
+		FileRefs:  []string{"models/**/*.onnx:application/x-onnx"},
+		FilesFrom: "list.txt",
+		Excludes:  []string{"models/tmp/b.onnx"},
+	}
+	if err := opts.parseFileRefs(); err != nil {
+		t.Fatal("Packer.parseFileRefs() error =", err)
+	}
+	want := []string{"models/a.onnx:application/x-onnx", "notes.txt:text/plain"}
+	if !reflect.DeepEqual(opts.FileRefs, want) {
+		t.Errorf("Packer.FileRefs = %v, want %v", opts.FileRefs, want)
+	}
+
+	// files named explicitly are not excluded
+	opts = Packer{
+		FileRefs: []string{"models/c.onnx", "models/tmp/b.onnx"},
+		Excludes: []string{"models/tmp/b.onnx"},
+	}
+	if err := opts.parseFileRefs(); err != nil {
+		t.Fatal("Packer.parseFileRefs() error =", err)
+	}
+	want = []string{"models/c.onnx", "models/tmp/b.onnx"}
+	if !reflect.DeepEqual(opts.FileRefs, want) {
+		t.Errorf("Packer.FileRefs = %v, want %v", opts.FileRefs, want)
+	}
+
+	for _, opts := range []Packer{
+		{FileRefs: []string{"missing/*.onnx"}},
+		{FileRefs: []string{"models/tmp/*", "models/c.*"}, Excludes: []string{"models/tmp/b.onnx"}},
+	} {
+		if err := opts.parseFileRefs(); err == nil {
+			t.Errorf("Packer.parseFileRefs(%v) error = nil, want error", opts.FileRefs)
+		}
+	}
+}
+
+func TestPacker_parseMediaTypeMap(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	if err := os.WriteFile(valid, []byte(`".onnx": application/vnd.onnx`), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{".onnx": "onnx"}`), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+
+	opts := Packer{MediaTypeMap: valid}
+	if err := opts.parseMediaTypeMap(); err != nil {
+		t.Fatal("Packer.parseMediaTypeMap() error =", err)
+	}
+	if !opts.InferMediaType {
+		t.Error("Packer.InferMediaType = false, want true")
+	}
+	if want := map[string]string{".onnx": "application/vnd.onnx"}; !reflect.DeepEqual(opts.MediaTypeMapping, want) {
+		t.Errorf("Packer.MediaTypeMapping = %v, want %v", opts.MediaTypeMapping, want)
+	}
+	if inferrer := opts.NewMediaTypeInferrer(); inferrer == nil {
+		t.Error("Packer.NewMediaTypeInferrer() = nil, want inferrer")
+	}
+
+	opts = Packer{MediaTypeMap: invalid}
+	if err := opts.parseMediaTypeMap(); err == nil {
+		t.Error("Packer.parseMediaTypeMap() error = nil, want error")
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package option
+
+import (
+	"github.com/spf13/pflag"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras/internal/upload"
+)
+
+// Upload option struct.
+type Upload struct {
+	ChunkSize ByteSize
+}
+
+// ApplyFlags applies flags to a command flag set.
+func (opts *Upload) ApplyFlags(fs *pflag.FlagSet) {
+	fs.Var(&opts.ChunkSize, "chunk-size", "[Experimental] upload blobs larger than `size` in chunks and resume interrupted uploads on re-run, e.g. 64MiB")
+}
+
+// ResumableTarget wraps target to upload blobs in chunks if --chunk-size is
+// specified. Targets other than remote repositories are returned as is.
+func (opts *Upload) ResumableTarget(target oras.GraphTarget) (oras.GraphTarget, error) {
+	repo, ok := target.(*remote.Repository)
+	if !ok || opts.ChunkSize <= 0 {
+		return target, nil
+	}
+	journal, err := upload.DefaultJournal()
+	if err != nil {
+		return nil, err
+	}
+	return upload.NewRepository(repo, int64(opts.ChunkSize), journal), nil
+}
+
+// StreamingTarget returns the repository for pushing blobs with digests
+// computed while uploading, honoring --chunk-size. nil is returned if target
+// requires the digests before pushing, e.g. an OCI image layout.
+func (opts *Upload) StreamingTarget(target oras.Target) (*upload.Repository, error) {
+	switch t := target.(type) {
+	case *upload.Repository:
+		return t, nil
+	case *remote.Repository:
+		if opts.ChunkSize <= 0 {
+			return upload.NewRepository(t, 0, nil), nil
+		}
+		journal, err := upload.DefaultJournal()
+		if err != nil {
+			return nil, err
+		}
+		return upload.NewRepository(t, int64(opts.ChunkSize), journal), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package option
+
+import (
+	"testing"
+
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras/internal/upload"
+)
+
+func TestUpload_ResumableTarget(t *testing.T) {
+	repo, err := remote.NewRepository("localhost:5000/test")
+	if err != nil {
+		t.Fatal("NewRepository() error =", err)
+	}
+	store := memory.New()
+
+	// disabled
+	opts := Upload{}
+	if got, err := opts.ResumableTarget(repo); err != nil || got != repo {
+		t.Errorf("Upload.ResumableTarget() = %v, %v, want %v", got, err, repo)
+	}
+
+	// enabled
+	opts.ChunkSize = 1 << 20
+	got, err := opts.ResumableTarget(repo)
+	if err != nil {
+		t.Fatal("Upload.ResumableTarget() error =", err)
+	}
+	if r, ok := got.(*upload.Repository); !ok || r.Repository != repo || r.ChunkSize != 1<<20 {
+		t.Errorf("Upload.ResumableTarget() = %v, want chunked repository", got)
+	}
+
+	// ignored for other targets
+	if got, err := opts.ResumableTarget(store); err != nil || got != store {
+		t.Errorf("Upload.ResumableTarget() = %v, %v, want %v", got, err, store)
+	}
+}
@@ -36,6 +36,7 @@ import (
 )
 
 type attachOptions struct {
+	option.Cache
 	option.Common
 	option.Packer
 	option.Target
@@ -78,6 +79,9 @@ Example - Attach an artifact with manifest annotations:
 Example - Attach file 'hi.txt' and add manifest annotations:
   oras attach --artifact-type doc/example --annotation "key=val" localhost:5000/hello:v1 hi.txt
 
+Example - Attach file 'hi.txt' with standard annotations filled from the clock, the git repository and the CI environment:
+  oras attach --artifact-type doc/example --annotation-preset all localhost:5000/hello:v1 hi.txt
+
 Example - Attach file 'hi.txt' and format output in JSON:
   oras attach --artifact-type doc/example localhost:5000/hello:v1 hi.txt --format json
 
@@ -87,6 +91,13 @@ Example - Attach file 'hi.txt' and format output with Go template:
 Example - Attach file 'hi.txt' and export the pushed manifest to 'manifest.json':
   oras attach --artifact-type doc/example --export-manifest manifest.json localhost:5000/hello:v1 hi.txt
 
+Example - Attach directory 'docs' as a zstd compressed layer:
+  oras attach --artifact-type doc/example --compression zstd localhost:5000/hello:v1 docs
+
+Example - Attach file 'hi.txt' and store the referrer in the local cache for later pulls:
+  export ORAS_CACHE=~/.oras/cache
+  oras attach --populate-cache --artifact-type doc/example localhost:5000/hello:v1 hi.txt
+
 Example - Attach file to the manifest tagged 'v1' in an OCI image layout folder 'layout-dir':
   oras attach --oci-layout --artifact-type doc/example layout-dir:v1 hi.txt
 `,
@@ -122,6 +133,7 @@ Example - Attach file to the manifest tagged 'v1' in an OCI image layout folder
 	_ = cmd.MarkFlagRequired("artifact-type")
 	opts.EnableDistributionSpecFlag()
 	opts.SetTypes(option.FormatTypeText, option.FormatTypeJSON, option.FormatTypeGoTemplate)
+	opts.EnablePopulateFlag()
 	option.ApplyFlags(&opts, cmd.Flags())
 	return oerrors.Command(cmd, &opts.Target)
 }
@@ -142,17 +154,19 @@ func runAttach(cmd *cobra.Command, opts *attachOptions) error {
 		return err
 	}
 	defer store.Close()
+	packer := opts.NewArchivePacker()
+	defer packer.Close()
 
-	dst, err := opts.NewTarget(opts.Common, logger)
+	originalDst, err := opts.NewTarget(opts.Common, logger)
 	if err != nil {
 		return err
 	}
 	// add both pull and push scope hints for dst repository
 	// to save potential push-scope token requests during copy
-	ctx = registryutil.WithScopeHint(ctx, dst, auth.ActionPull, auth.ActionPush)
+	ctx = registryutil.WithScopeHint(ctx, originalDst, auth.ActionPull, auth.ActionPush)
 	fetchOpts := oras.DefaultResolveOptions
 	fetchOpts.TargetPlatform = opts.Platform.Platform
-	subject, err := oras.Resolve(ctx, dst, opts.Reference, fetchOpts)
+	subject, err := oras.Resolve(ctx, originalDst, opts.Reference, fetchOpts)
 	if err != nil {
 		return fmt.Errorf("failed to resolve %s: %w", opts.Reference, err)
 	}
@@ -160,13 +174,17 @@ func runAttach(cmd *cobra.Command, opts *attachOptions) error {
 	if err != nil {
 		return err
 	}
-	descs, err := loadFiles(ctx, store, opts.Annotations, opts.FileRefs, displayStatus)
+	descs, err := loadFiles(ctx, store, opts.Annotations, opts.FileRefs, displayStatus, fileLoadOptions{
+		packer:   packer,
+		inferrer: opts.NewMediaTypeInferrer(),
+		metadata: opts.NewMetadataOptions(),
+	})
 	if err != nil {
 		return err
 	}
 
 	// prepare push
-	dst, stopTrack, err := displayStatus.TrackTarget(dst)
+	dst, stopTrack, err := displayStatus.TrackTarget(originalDst)
 	if err != nil {
 		return err
 	}
@@ -213,6 +231,14 @@ func runAttach(cmd *cobra.Command, opts *attachOptions) error {
 		return err
 	}
 
+	// Populate cache without the subject
+	populateOpts := oras.DefaultCopyGraphOptions
+	populateOpts.Concurrency = opts.concurrency
+	populateOpts.FindSuccessors = graphCopyOptions.FindSuccessors
+	if err := opts.Populate(ctx, store, originalDst, root, nil, populateOpts); err != nil {
+		return err
+	}
+
 	// Export manifest
 	return opts.ExportManifest(ctx, store, root)
 }
@@ -31,6 +31,7 @@ import (
 	"oras.land/oras/cmd/oras/internal/display/status/track"
 	oerrors "oras.land/oras/cmd/oras/internal/errors"
 	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/internal/download"
 )
 
 type fetchBlobOptions struct {
@@ -41,6 +42,7 @@ type fetchBlobOptions struct {
 	option.Target
 
 	outputPath string
+	resume     bool
 }
 
 func fetchCmd() *cobra.Command {
@@ -53,6 +55,9 @@ func fetchCmd() *cobra.Command {
 Example - Fetch a blob from registry and save it to a local file:
   oras blob fetch --output blob.tar.gz localhost:5000/hello@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5
 
+Example - Fetch a blob from registry and save it to a local file, resuming an interrupted download:
+  oras blob fetch --resume --output blob.tar.gz localhost:5000/hello@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5
+
 Example - Fetch a blob from registry and print the raw blob content:
   oras blob fetch --output - localhost:5000/hello@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5
 
@@ -62,6 +67,10 @@ Example - Fetch and print the descriptor of a blob:
 Example - Fetch a blob, save it to a local file and print the descriptor:
   oras blob fetch --output blob.tar.gz --descriptor localhost:5000/hello@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5
 
+Example - Fetch a blob from registry with local cache and save it to a local file:
+  export ORAS_CACHE=~/.oras/cache
+  oras blob fetch --output blob.tar.gz localhost:5000/hello@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5
+
 Example - Fetch and print a blob from OCI image layout folder 'layout-dir':
   oras blob fetch --oci-layout --output - layout-dir@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5
 
@@ -91,6 +100,7 @@ Example - Fetch and print a blob from OCI image layout archive file 'layout.tar'
 	}
 
 	cmd.Flags().StringVarP(&opts.outputPath, "output", "o", "", "output file `path`, use - for stdout")
+	cmd.Flags().BoolVarP(&opts.resume, "resume", "", false, "[Experimental] keep the partially downloaded blob beside the output file and resume it on the next fetch, if not read from the local cache")
 	option.ApplyFlags(&opts, cmd.Flags())
 	return oerrors.Command(cmd, &opts.Target)
 }
@@ -107,13 +117,17 @@ func fetchBlob(cmd *cobra.Command, opts *fetchBlobOptions) (fetchErr error) {
 		return err
 	}
 
+	var isRemote bool
 	if repo, ok := target.(*remote.Repository); ok {
 		target = repo.Blobs()
+		isRemote = true
 	}
 	src, err := opts.CachedTarget(target)
 	if err != nil {
 		return err
 	}
+	// only downloads from the registry are resumed
+	opts.resume = opts.resume && isRemote && src == target
 	desc, err := opts.doFetch(ctx, src)
 	if err != nil {
 		return err
@@ -141,7 +155,15 @@ func (opts *fetchBlobOptions) doFetch(ctx context.Context, src oras.ReadOnlyTarg
 	}
 	// fetch blob content
 	var rc io.ReadCloser
-	desc, rc, err = oras.Fetch(ctx, src, opts.Reference, oras.DefaultFetchOptions)
+	if opts.outputPath != "-" && opts.resume {
+		// keep the partially downloaded blob beside the output to resume later
+		desc, err = oras.Resolve(ctx, src, opts.Reference, oras.DefaultResolveOptions)
+		if err == nil {
+			rc, err = download.Fetch(ctx, src, desc, opts.outputPath+".partial")
+		}
+	} else {
+		desc, rc, err = oras.Fetch(ctx, src, opts.Reference, oras.DefaultFetchOptions)
+	}
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
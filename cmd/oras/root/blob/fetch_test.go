@@ -20,6 +20,8 @@ package blob
 import (
 	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/opencontainers/go-digest"
@@ -65,3 +67,38 @@ func Test_fetchBlobOptions_doFetch(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func Test_fetchBlobOptions_doFetch_resume(t *testing.T) {
+	src := memory.New()
+	content := []byte("test")
+	desc := ocispec.Descriptor{
+		MediaType: "application/octet-stream",
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	ctx := context.Background()
+	if err := src.Push(ctx, desc, bytes.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Tag(ctx, desc, "blob"); err != nil {
+		t.Fatal(err)
+	}
+	var opts fetchBlobOptions
+	opts.Reference = "blob"
+	opts.outputPath = filepath.Join(t.TempDir(), "test")
+	opts.resume = true
+	if _, err := opts.doFetch(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(opts.outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("fetched content = %q, want %q", got, content)
+	}
+	// the partial download is removed once completed
+	if _, err := os.Stat(opts.outputPath + ".partial"); !os.IsNotExist(err) {
+		t.Errorf("partial download is not removed: %v", err)
+	}
+}
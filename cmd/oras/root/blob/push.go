@@ -17,10 +17,10 @@ package blob
 
 import (
 	"context"
-	"errors"
 	"io"
 	"os"
 
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"oras.land/oras-go/v2"
@@ -31,6 +31,7 @@ import (
 	"oras.land/oras/cmd/oras/internal/option"
 	"oras.land/oras/cmd/oras/internal/output"
 	"oras.land/oras/internal/file"
+	"oras.land/oras/internal/upload"
 )
 
 type pushBlobOptions struct {
@@ -38,6 +39,7 @@ type pushBlobOptions struct {
 	option.Descriptor
 	option.Pretty
 	option.Target
+	option.Upload
 
 	fileRef   string
 	mediaType string
@@ -61,6 +63,9 @@ Example - Push blob 'hi.txt' with the specific digest:
 Example - Push blob from stdin with blob size and digest:
   oras blob push --size 12 localhost:5000/hello@sha256:9a201d228ebd966211f7d1131be19f152be428bd373a92071c71d8deaf83b3e5 -
 
+Example - Push blob from stdin with unknown size and digest, and output the computed descriptor:
+  tar c dir | oras blob push --descriptor localhost:5000/hello -
+
 Example - Push blob 'hi.txt' and output the descriptor:
   oras blob push --descriptor localhost:5000/hello hi.txt
 
@@ -70,6 +75,9 @@ Example - Push blob 'hi.txt' with the specific returned media type in the descri
 Example - Push blob 'hi.txt' and output the prettified descriptor:
   oras blob push --descriptor --pretty localhost:5000/hello hi.txt
 
+Example - Push large blob 'model.bin' in chunks of 64 MiB and resume the upload if interrupted:
+  oras blob push --chunk-size 64MiB localhost:5000/hello model.bin
+
 Example - Push blob without TLS:
   oras blob push --insecure localhost:5000/hello hi.txt
 
@@ -84,9 +92,6 @@ Example - Push blob 'hi.txt' into an OCI image layout folder 'layout-dir':
 				if err := option.CheckStdinConflict(cmd.Flags()); err != nil {
 					return err
 				}
-				if opts.size < 0 {
-					return errors.New("`--size` must be provided if the blob is read from stdin")
-				}
 			}
 			return option.Parse(cmd, &opts)
 		},
@@ -110,22 +115,22 @@ func pushBlob(cmd *cobra.Command, opts *pushBlobOptions) (err error) {
 	if err != nil {
 		return err
 	}
-
-	// prepare blob content
-	desc, rc, err := file.PrepareBlobContent(opts.fileRef, opts.mediaType, opts.Reference, opts.size)
+	target, err = opts.ResumableTarget(target)
 	if err != nil {
 		return err
 	}
-	defer rc.Close()
 
-	exists, err := target.Exists(ctx, desc)
+	var desc ocispec.Descriptor
+	streamer, err := opts.StreamingTarget(target)
 	if err != nil {
 		return err
 	}
-	if exists {
-		err = opts.Printer.PrintStatus(desc, "Exists")
+	if streamer != nil && opts.fileRef == "-" && (opts.Reference == "" || opts.size < 0) {
+		// hash the blob from stdin while uploading it instead of spooling it,
+		// as stdin cannot be read twice
+		desc, err = opts.doPushStream(ctx, opts.Printer, streamer)
 	} else {
-		err = opts.doPush(ctx, opts.Printer, target, desc, rc)
+		desc, err = opts.prepareAndPush(ctx, target)
 	}
 	if err != nil {
 		return err
@@ -144,27 +149,80 @@ func pushBlob(cmd *cobra.Command, opts *pushBlobOptions) (err error) {
 
 	return nil
 }
+
+// prepareAndPush pushes the blob with the digest computed or provided before
+// pushing, skipping the blob if it exists.
+func (opts *pushBlobOptions) prepareAndPush(ctx context.Context, target oras.Target) (ocispec.Descriptor, error) {
+	// prepare blob content
+	desc, rc, err := file.PrepareBlobContent(opts.fileRef, opts.mediaType, opts.Reference, opts.size)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer rc.Close()
+
+	exists, err := target.Exists(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if exists {
+		err = opts.Printer.PrintStatus(desc, "Exists")
+	} else {
+		err = opts.doPush(ctx, opts.Printer, target, desc, rc)
+	}
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
+
+// doPushStream pushes the blob read from stdin with the digest computed while
+// uploading. The existence of the blob is not checked as the digest is unknown
+// before uploading.
+func (opts *pushBlobOptions) doPushStream(ctx context.Context, printer *output.Printer, s *upload.Repository) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{
+		MediaType: opts.mediaType,
+		Digest:    digest.Digest(opts.Reference),
+		Size:      opts.size,
+	}
+	err := opts.trackPush(printer, &desc, os.Stdin, func(r io.Reader) error {
+		pushed, err := s.PushStream(ctx, desc, r, "")
+		desc.Digest, desc.Size = pushed.Digest, pushed.Size
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
+
 func (opts *pushBlobOptions) doPush(ctx context.Context, printer *output.Printer, t oras.Target, desc ocispec.Descriptor, r io.Reader) error {
-	if opts.TTY == nil {
-		// none TTY output
-		if err := printer.PrintStatus(desc, "Uploading"); err != nil {
+	return opts.trackPush(printer, &desc, r, func(r io.Reader) error {
+		return t.Push(ctx, desc, r)
+	})
+}
+
+// trackPush pushes the content read from r via push, and prints the
+// progress of desc, which may be updated by push.
+func (opts *pushBlobOptions) trackPush(printer *output.Printer, desc *ocispec.Descriptor, r io.Reader, push func(r io.Reader) error) error {
+	if opts.TTY == nil || desc.Size < 0 {
+		// none TTY output, or the progress is unknown
+		if err := printer.PrintStatus(*desc, "Uploading"); err != nil {
 			return err
 		}
-		if err := t.Push(ctx, desc, r); err != nil {
+		if err := push(r); err != nil {
 			return err
 		}
-		return printer.PrintStatus(desc, "Uploaded ")
+		return printer.PrintStatus(*desc, "Uploaded ")
 	}
 
 	// TTY output
-	trackedReader, err := track.NewReader(r, desc, "Uploading", "Uploaded ", opts.TTY)
+	trackedReader, err := track.NewReader(r, *desc, "Uploading", "Uploaded ", opts.TTY)
 	if err != nil {
 		return err
 	}
 	defer trackedReader.StopManager()
 	trackedReader.Start()
-	r = trackedReader
-	if err := t.Push(ctx, desc, r); err != nil {
+	if err := push(trackedReader); err != nil {
 		return err
 	}
 	trackedReader.Done()
@@ -20,7 +20,13 @@ package blob
 import (
 	"bytes"
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"oras.land/oras/cmd/oras/internal/output"
@@ -28,7 +34,9 @@ import (
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras/internal/testutils"
+	"oras.land/oras/internal/upload"
 )
 
 func Test_pushBlobOptions_doPush(t *testing.T) {
@@ -59,3 +67,111 @@ func Test_pushBlobOptions_doPush(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// countingTarget counts the pushes to the target.
+type countingTarget struct {
+	*memory.Store
+	pushed int
+}
+
+func (t *countingTarget) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	t.pushed++
+	return t.Store.Push(ctx, expected, content)
+}
+
+func Test_pushBlobOptions_prepareAndPush(t *testing.T) {
+	content := []byte("test")
+	path := filepath.Join(t.TempDir(), "blob")
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	want := ocispec.Descriptor{
+		MediaType: "application/octet-stream",
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	target := &countingTarget{Store: memory.New()}
+
+	var opts pushBlobOptions
+	opts.fileRef = path
+	opts.mediaType = want.MediaType
+	opts.size = -1
+	opts.Printer = output.NewPrinter(io.Discard, os.Stderr)
+	for i := 0; i < 2; i++ {
+		// the blob is hashed before pushing, and skipped if it exists
+		got, err := opts.prepareAndPush(context.Background(), target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("prepareAndPush() = %v, want %v", got, want)
+		}
+	}
+	if target.pushed != 1 {
+		t.Errorf("prepareAndPush() pushed %d times, want 1", target.pushed)
+	}
+}
+
+func Test_pushBlobOptions_doPushStream_stdin(t *testing.T) {
+	content := []byte("test")
+	want := ocispec.Descriptor{
+		MediaType: "application/octet-stream",
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	var uploaded []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Location", "/v2/test/blobs/uploads/1")
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPatch:
+			data, _ := io.ReadAll(r.Body)
+			uploaded = append(uploaded, data...)
+			w.Header().Set("Location", "/v2/test/blobs/uploads/1")
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPut:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer ts.Close()
+	uri, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := remote.NewRepository(uri.Host + "/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.PlainHTTP = true
+
+	stdin, err := os.Create(filepath.Join(t.TempDir(), "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	if _, err := stdin.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	defer func(stdin *os.File) { os.Stdin = stdin }(os.Stdin)
+	os.Stdin = stdin
+
+	var opts pushBlobOptions
+	opts.fileRef = "-"
+	opts.mediaType = want.MediaType
+	opts.size = -1
+	printer := output.NewPrinter(os.Stdout, os.Stderr)
+	got, err := opts.doPushStream(context.Background(), printer, upload.NewRepository(repo, 0, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doPushStream() = %v, want %v", got, want)
+	}
+	if !bytes.Equal(uploaded, content) {
+		t.Errorf("uploaded %q, want %q", uploaded, content)
+	}
+}
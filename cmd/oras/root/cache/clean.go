@@ -0,0 +1,87 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"oras.land/oras/cmd/oras/internal/command"
+	"oras.land/oras/cmd/oras/internal/display/status/progress/humanize"
+	"oras.land/oras/cmd/oras/internal/option"
+)
+
+type cleanOptions struct {
+	option.Cache
+	option.Common
+	option.Confirmation
+}
+
+func cleanCmd() *cobra.Command {
+	var opts cleanOptions
+	cmd := &cobra.Command{
+		Use:   "clean [flags]",
+		Short: "[Experimental] Remove all content from the local cache",
+		Long: `[Experimental] Remove all content from the local cache
+
+Example - Remove all content from the local cache:
+  oras cache clean
+
+Example - Remove all content from the local cache without prompting confirmation:
+  oras cache clean --force
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return option.Parse(cmd, &opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cleanCache(cmd, &opts)
+		},
+	}
+
+	option.ApplyFlags(&opts, cmd.Flags())
+	return cmd
+}
+
+func cleanCache(cmd *cobra.Command, opts *cleanOptions) error {
+	ctx, _ := command.GetLogger(cmd, &opts.Common)
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+
+	prompt := fmt.Sprintf("Are you sure you want to remove all content from the cache %q?", store.Root())
+	confirmed, err := opts.AskForConfirmation(os.Stdin, prompt)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	removed, err := store.Clean(ctx)
+	var reclaimed int64
+	for _, b := range removed {
+		reclaimed += b.Size
+	}
+	if err != nil {
+		return err
+	}
+	_ = opts.Printer.Println("Removed", len(removed), "blobs from", store.Root())
+	_ = opts.Printer.Println("Reclaimed", humanize.ToBytes(reclaimed))
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"github.com/spf13/cobra"
+	"oras.land/oras/cmd/oras/internal/command"
+	"oras.land/oras/cmd/oras/internal/display/status/progress/humanize"
+	"oras.land/oras/cmd/oras/internal/option"
+)
+
+type duOptions struct {
+	option.Cache
+	option.Common
+}
+
+func duCmd() *cobra.Command {
+	var opts duOptions
+	cmd := &cobra.Command{
+		Use:   "du [flags]",
+		Short: "[Experimental] Show the disk usage of the local cache",
+		Long: `[Experimental] Show the disk usage of the local cache
+
+Example - Show the disk usage of the local cache:
+  export ORAS_CACHE=~/.oras/cache
+  oras cache du
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return option.Parse(cmd, &opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diskUsage(cmd, &opts)
+		},
+	}
+
+	option.ApplyFlags(&opts, cmd.Flags())
+	return cmd
+}
+
+func diskUsage(cmd *cobra.Command, opts *duOptions) error {
+	ctx, _ := command.GetLogger(cmd, &opts.Common)
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+	size, count, err := store.Size(ctx)
+	if err != nil {
+		return err
+	}
+	_ = opts.Printer.Println("Cache:", store.Root())
+	_ = opts.Printer.Println("Blobs:", count)
+	_ = opts.Printer.Println("Size: ", humanize.ToBytes(size))
+	if store.MaxSize > 0 {
+		_ = opts.Printer.Println("Limit:", humanize.ToBytes(store.MaxSize))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"oras.land/oras/cmd/oras/internal/command"
+	"oras.land/oras/cmd/oras/internal/display/status/progress/humanize"
+	"oras.land/oras/cmd/oras/internal/option"
+)
+
+type listOptions struct {
+	option.Cache
+	option.Common
+}
+
+func listCmd() *cobra.Command {
+	var opts listOptions
+	cmd := &cobra.Command{
+		Use:   "ls [flags]",
+		Short: "[Experimental] List blobs in the local cache",
+		Long: `[Experimental] List blobs in the local cache, from the most recently used to the least recently used
+
+Example - List blobs in the local cache:
+  export ORAS_CACHE=~/.oras/cache
+  oras cache ls
+`,
+		Args:    cobra.NoArgs,
+		Aliases: []string{"list"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return option.Parse(cmd, &opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listCache(cmd, &opts)
+		},
+	}
+
+	option.ApplyFlags(&opts, cmd.Flags())
+	return cmd
+}
+
+func listCache(cmd *cobra.Command, opts *listOptions) error {
+	ctx, _ := command.GetLogger(cmd, &opts.Common)
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+	blobs, err := store.Blobs(ctx)
+	if err != nil {
+		return err
+	}
+	slices.Reverse(blobs)
+
+	w := tabwriter.NewWriter(opts.Printer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DIGEST\tSIZE\tLAST ACCESSED")
+	for _, b := range blobs {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", b.Digest, humanize.ToBytes(b.Size), b.LastAccessed.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
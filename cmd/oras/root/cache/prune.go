@@ -0,0 +1,94 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	"oras.land/oras/cmd/oras/internal/command"
+	"oras.land/oras/cmd/oras/internal/display/status/progress/humanize"
+	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/internal/cache"
+)
+
+type pruneOptions struct {
+	option.Cache
+	option.Common
+
+	maxSize   option.ByteSize
+	olderThan time.Duration
+}
+
+func pruneCmd() *cobra.Command {
+	var opts pruneOptions
+	cmd := &cobra.Command{
+		Use:   "prune [flags] {--max-size <size> | --older-than <duration>}",
+		Short: "[Experimental] Evict least recently used blobs from the local cache",
+		Long: `[Experimental] Evict least recently used blobs from the local cache
+
+Example - Shrink the local cache to at most 10 GiB:
+  oras cache prune --max-size 10GiB
+
+Example - Evict blobs which have not been used in the last 7 days:
+  oras cache prune --older-than 168h
+
+Example - Evict stale blobs and limit the cache size at the same time:
+  oras cache prune --older-than 168h --max-size 10GiB
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("max-size") && !cmd.Flags().Changed("older-than") {
+				return errors.New("at least one of `--max-size` and `--older-than` must be provided")
+			}
+			if opts.maxSize < 0 || opts.olderThan < 0 {
+				return errors.New("`--max-size` and `--older-than` cannot be negative")
+			}
+			return option.Parse(cmd, &opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pruneCache(cmd, &opts)
+		},
+	}
+
+	cmd.Flags().Var(&opts.maxSize, "max-size", "evict least recently used blobs until the cache is no larger than `size`, e.g. 10GiB")
+	cmd.Flags().DurationVar(&opts.olderThan, "older-than", 0, "evict blobs not used within the `duration`, e.g. 168h")
+	option.ApplyFlags(&opts, cmd.Flags())
+	return cmd
+}
+
+func pruneCache(cmd *cobra.Command, opts *pruneOptions) error {
+	ctx, _ := command.GetLogger(cmd, &opts.Common)
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+	evicted, err := store.Prune(ctx, cache.PruneOptions{
+		MaxSize:   int64(opts.maxSize),
+		OlderThan: opts.olderThan,
+	})
+	var reclaimed int64
+	for _, b := range evicted {
+		_ = opts.Printer.Println("Evicted", b.Digest)
+		reclaimed += b.Size
+	}
+	if err != nil {
+		return err
+	}
+	_ = opts.Printer.Println("Reclaimed", humanize.ToBytes(reclaimed), "from", len(evicted), "blobs")
+	return nil
+}
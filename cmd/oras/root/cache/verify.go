@@ -0,0 +1,97 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"oras.land/oras/cmd/oras/internal/command"
+	oerrors "oras.land/oras/cmd/oras/internal/errors"
+	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/internal/cache"
+)
+
+type verifyOptions struct {
+	option.Cache
+	option.Common
+
+	evict bool
+}
+
+func verifyCmd() *cobra.Command {
+	var opts verifyOptions
+	cmd := &cobra.Command{
+		Use:   "verify [flags]",
+		Short: "[Experimental] Verify the integrity of blobs in the local cache",
+		Long: `[Experimental] Verify the integrity of blobs in the local cache
+
+Example - Verify the digests of all blobs in the local cache:
+  oras cache verify
+
+Example - Verify all blobs and evict corrupted ones from the local cache:
+  oras cache verify --evict
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return option.Parse(cmd, &opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verifyCache(cmd, &opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.evict, "evict", "", false, "evict corrupted blobs from the local cache")
+	option.ApplyFlags(&opts, cmd.Flags())
+	return cmd
+}
+
+func verifyCache(cmd *cobra.Command, opts *verifyOptions) error {
+	ctx, _ := command.GetLogger(cmd, &opts.Common)
+	store, err := opts.NewStore()
+	if err != nil {
+		return err
+	}
+
+	var verified int
+	var corrupted []cache.Blob
+	if err := store.Verify(ctx, func(b cache.Blob, verifyErr error) error {
+		verified++
+		if verifyErr == nil {
+			return nil
+		}
+		corrupted = append(corrupted, b)
+		_ = opts.Printer.Println("Corrupted", b.Digest)
+		if opts.evict {
+			if err := store.Delete(ctx, ocispec.Descriptor{Digest: b.Digest, Size: b.Size}); err != nil {
+				return err
+			}
+			_ = opts.Printer.Println("Evicted", b.Digest)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	_ = opts.Printer.Println("Verified", verified, "blobs,", len(corrupted), "corrupted")
+	if len(corrupted) > 0 && !opts.evict {
+		return &oerrors.Error{
+			Err:            fmt.Errorf("found %d corrupted blobs in %s", len(corrupted), store.Root()),
+			Recommendation: "Run the command again with `--evict` to evict corrupted blobs from the local cache",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras/cmd/oras/internal/output"
+	"oras.land/oras/internal/cache"
+)
+
+func Test_verifyCache(t *testing.T) {
+	// prepare
+	root := t.TempDir()
+	t.Setenv("ORAS_CACHE", root)
+	store, err := cache.NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	blob := []byte("hello world")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	if err := store.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("HELLO WORLD"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	out := &bytes.Buffer{}
+	var opts verifyOptions
+	opts.Printer = output.NewPrinter(out, out)
+
+	// test without eviction
+	if err := verifyCache(cmd, &opts); err == nil {
+		t.Fatal("verifyCache() error = nil, want error for corrupted blob")
+	}
+	if got := out.String(); !strings.Contains(got, "Corrupted "+desc.Digest.String()) {
+		t.Errorf("verifyCache() output = %q, want corrupted %s", got, desc.Digest)
+	}
+
+	// test with eviction
+	out.Reset()
+	opts.evict = true
+	if err := verifyCache(cmd, &opts); err != nil {
+		t.Fatal("verifyCache() error =", err)
+	}
+	if got := out.String(); !strings.Contains(got, "Evicted "+desc.Digest.String()) {
+		t.Errorf("verifyCache() output = %q, want evicted %s", got, desc.Digest)
+	}
+	exists, err := store.Exists(ctx, desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Errorf("corrupted blob %s still exists after verification", desc.Digest)
+	}
+}
@@ -18,6 +18,7 @@ package root
 import (
 	"github.com/spf13/cobra"
 	"oras.land/oras/cmd/oras/root/blob"
+	"oras.land/oras/cmd/oras/root/cache"
 	"oras.land/oras/cmd/oras/root/manifest"
 	"oras.land/oras/cmd/oras/root/repo"
 )
@@ -35,10 +36,12 @@ func New() *cobra.Command {
 		versionCmd(),
 		discoverCmd(),
 		resolveCmd(),
+		verifyCmd(),
 		copyCmd(),
 		tagCmd(),
 		attachCmd(),
 		blob.Cmd(),
+		cache.Cmd(),
 		manifest.Cmd(),
 		repo.Cmd(),
 	)
@@ -43,6 +43,7 @@ import (
 )
 
 type copyOptions struct {
+	option.Cache
 	option.Common
 	option.Platform
 	option.BinaryTarget
@@ -80,6 +81,14 @@ Example - Copy an artifact and referrers using specific methods for the Referrer
   oras cp -r --from-distribution-spec v1.1-referrers-api --to-distribution-spec v1.1-referrers-tag \
     localhost:5000/net-monitor:v1 localhost:6000/net-monitor-copy:v1
 
+Example - Copy an artifact between registries with local cache:
+  export ORAS_CACHE=~/.oras/cache
+  oras cp localhost:5000/net-monitor:v1 localhost:6000/net-monitor-copy:v1
+
+Example - Copy an artifact and store it in the local cache for later pulls from the destination:
+  export ORAS_CACHE=~/.oras/cache
+  oras cp --populate-cache localhost:5000/net-monitor:v1 localhost:6000/net-monitor-copy:v1
+
 Example - Copy certain platform of an artifact:
   oras cp --platform linux/arm/v5 localhost:5000/net-monitor:v1 localhost:6000/net-monitor-copy:v1
 
@@ -106,6 +115,7 @@ Example - Copy an artifact with multiple tags with concurrency tuned:
 	cmd.Flags().IntVarP(&opts.concurrency, "concurrency", "", 3, "concurrency level")
 	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "print status output for unnamed blobs")
 	opts.EnableDistributionSpecFlag()
+	opts.EnablePopulateFlag()
 	option.ApplyFlags(&opts, cmd.Flags())
 	return oerrors.Command(cmd, &opts.BinaryTarget)
 }
@@ -150,6 +160,14 @@ func runCopy(cmd *cobra.Command, opts *copyOptions) error {
 		}
 	}
 
+	// The content fetched from the source is cached while copying, so only the
+	// tags are left to populate. Content not fetched, e.g. mounted blobs or
+	// content already in the destination, is not cached
+	tags := append([]string{opts.To.Reference}, opts.extraRefs...)
+	if err := opts.RecordTags(ctx, dst, desc, tags); err != nil {
+		return err
+	}
+
 	_ = opts.Printer.Println("Digest:", desc.Digest)
 
 	return nil
@@ -170,6 +188,10 @@ func doCopy(ctx context.Context, copyHandler status.CopyHandler, src oras.ReadOn
 			return []string{srcRepo.Reference.Repository}, nil
 		}
 	}
+	src, err = opts.CachedGraphTarget(src)
+	if err != nil {
+		return desc, err
+	}
 	dst, err = copyHandler.StartTracking(dst)
 	if err != nil {
 		return desc, err
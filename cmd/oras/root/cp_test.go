@@ -32,6 +32,7 @@ import (
 	"oras.land/oras-go/v2/content/memory"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras/cmd/oras/internal/display/status"
+	"oras.land/oras/internal/cache"
 	"oras.land/oras/internal/testutils"
 )
 
@@ -197,3 +198,40 @@ func Test_doCopy_mounted(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func Test_doCopy_cached(t *testing.T) {
+	// prepare
+	pty, slave, err := testutils.NewPty()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slave.Close()
+	cacheRoot := t.TempDir()
+	t.Setenv("ORAS_CACHE", cacheRoot)
+	var opts copyOptions
+	opts.TTY = slave
+	opts.From.Reference = memDesc.Digest.String()
+	dst := memory.New()
+	handler := status.NewTTYCopyHandler(opts.TTY)
+
+	// test
+	_, err = doCopy(context.Background(), handler, memStore, dst, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// validate
+	if err = testutils.MatchPty(pty, slave, "Copied", memDesc.MediaType, "100.00%", memDesc.Digest.String()); err != nil {
+		t.Fatal(err)
+	}
+	store, err := cache.NewStore(cacheRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exists, err := store.Exists(context.Background(), memDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatalf("copied content %s is not cached", memDesc.Digest)
+	}
+}
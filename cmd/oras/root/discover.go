@@ -34,6 +34,7 @@ import (
 )
 
 type discoverOptions struct {
+	option.Cache
 	option.Common
 	option.Platform
 	option.Target
@@ -73,6 +74,10 @@ Example - Discover all the referrers of manifest with annotations, displayed in
 Example - Discover referrers with type 'test-artifact' of manifest 'hello:v1' in registry 'localhost:5000':
   oras discover --artifact-type test-artifact localhost:5000/hello:v1
 
+Example - Discover referrers from a registry with local cache:
+  export ORAS_CACHE=~/.oras/cache
+  oras discover localhost:5000/hello:v1
+
 Example - Discover referrers of the manifest tagged 'v1' in an OCI image layout folder 'layout-dir':
   oras discover --oci-layout layout-dir:v1
 `,
@@ -116,13 +121,17 @@ Example - Discover referrers of the manifest tagged 'v1' in an OCI image layout
 
 func runDiscover(cmd *cobra.Command, opts *discoverOptions) error {
 	ctx, logger := command.GetLogger(cmd, &opts.Common)
-	repo, err := opts.NewReadonlyTarget(ctx, opts.Common, logger)
+	target, err := opts.NewReadonlyTarget(ctx, opts.Common, logger)
 	if err != nil {
 		return err
 	}
 	if err := opts.EnsureReferenceNotEmpty(cmd, true); err != nil {
 		return err
 	}
+	repo, err := opts.CachedGraphTarget(target)
+	if err != nil {
+		return err
+	}
 
 	// discover artifacts
 	resolveOpts := oras.DefaultResolveOptions
@@ -19,15 +19,35 @@ import (
 	"context"
 	"errors"
 	"io/fs"
+	"maps"
+	"os"
 	"path/filepath"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/content/file"
 	"oras.land/oras/cmd/oras/internal/display/status"
 	"oras.land/oras/cmd/oras/internal/fileref"
+	"oras.land/oras/internal/archive"
+	ofile "oras.land/oras/internal/file"
+	"oras.land/oras/internal/mediatype"
 )
 
-func loadFiles(ctx context.Context, store *file.Store, annotations map[string]map[string]string, fileRefs []string, displayStatus status.PushHandler) ([]ocispec.Descriptor, error) {
+// fileLoadOptions configures how files are loaded.
+type fileLoadOptions struct {
+	// packer packs directories if not nil.
+	packer *archive.Packer
+	// inferrer infers the media types of files without explicit types if not
+	// nil.
+	inferrer *mediatype.Inferrer
+	// metadata controls how the metadata of regular files and symbolic links
+	// to them is recorded as annotations.
+	metadata ofile.MetadataOptions
+	// chunks stores the chunks of files larger than splitSize if not nil.
+	chunks    *archive.ChunkStore
+	splitSize int64
+}
+
+func loadFiles(ctx context.Context, store *file.Store, annotations map[string]map[string]string, fileRefs []string, displayStatus status.PushHandler, loadOpts fileLoadOptions) ([]ocispec.Descriptor, error) {
 	var files []ocispec.Descriptor
 	for _, fileRef := range fileRefs {
 		filename, mediaType, err := fileref.Parse(fileRef, "")
@@ -45,20 +65,59 @@ func loadFiles(ctx context.Context, store *file.Store, annotations map[string]ma
 		if err != nil {
 			return nil, err
 		}
-		file, err := addFile(ctx, store, name, mediaType, filename)
-		if err != nil {
-			return nil, err
+		fi, statErr := os.Stat(filename)
+		// infer the media type only if no type is specified in the reference
+		if loadOpts.inferrer != nil && mediaType == "" && statErr == nil && fi.Mode().IsRegular() {
+			if mediaType, err = loadOpts.inferrer.Infer(filename); err != nil {
+				return nil, err
+			}
+		}
+		var descs []ocispec.Descriptor
+		switch {
+		case loadOpts.packer != nil && statErr == nil && fi.IsDir():
+			file, err := addDirectory(ctx, store, loadOpts.packer, name, mediaType, filename)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, file)
+		case loadOpts.chunks != nil && statErr == nil && fi.Mode().IsRegular() && fi.Size() > loadOpts.splitSize:
+			if mediaType == "" {
+				mediaType = ocispec.MediaTypeImageLayer
+			}
+			if descs, err = loadOpts.chunks.Split(filename, name, mediaType, loadOpts.splitSize); err != nil {
+				return nil, err
+			}
+		default:
+			file, err := addFile(ctx, store, name, mediaType, filename)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, file)
+		}
+		if statErr == nil && fi.Mode().IsRegular() {
+			metadata, err := ofile.MetadataAnnotations(filename, loadOpts.metadata)
+			if err != nil {
+				return nil, err
+			}
+			for i := range descs {
+				if descs[i].Annotations == nil {
+					descs[i].Annotations = make(map[string]string)
+				}
+				maps.Copy(descs[i].Annotations, metadata)
+			}
 		}
-		if value, ok := annotations[filename]; ok {
-			if file.Annotations == nil {
-				file.Annotations = value
-			} else {
-				for k, v := range value {
-					file.Annotations[k] = v
+		for _, file := range descs {
+			if value, ok := annotations[filename]; ok {
+				if file.Annotations == nil {
+					file.Annotations = maps.Clone(value)
+				} else {
+					for k, v := range value {
+						file.Annotations[k] = v
+					}
 				}
 			}
+			files = append(files, file)
 		}
-		files = append(files, file)
 	}
 	if len(files) == 0 {
 		if err := displayStatus.OnEmptyArtifact(); err != nil {
@@ -79,3 +138,25 @@ func addFile(ctx context.Context, store *file.Store, name string, mediaType stri
 	}
 	return file, nil
 }
+
+// addDirectory packs the directory with packer and adds the packed tarball to
+// store, annotated to be unpacked on pull.
+func addDirectory(ctx context.Context, store *file.Store, packer *archive.Packer, name string, mediaType string, dir string) (ocispec.Descriptor, error) {
+	path, tarDigest, err := packer.Pack(dir, name)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if mediaType == "" {
+		mediaType = packer.Compression.MediaType()
+	}
+	desc, err := addFile(ctx, store, name, mediaType, path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if desc.Annotations == nil {
+		desc.Annotations = make(map[string]string)
+	}
+	desc.Annotations[file.AnnotationDigest] = tarDigest.String()
+	desc.Annotations[file.AnnotationUnpack] = "true"
+	return desc, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras/cmd/oras/internal/display/status"
+	"oras.land/oras/internal/archive"
+	ofile "oras.land/oras/internal/file"
+	"oras.land/oras/internal/mediatype"
+)
+
+func Test_addDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hi.txt"), []byte("hi"), 0600); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	store, err := file.New("")
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+	packer := archive.NewPacker(archive.Options{Reproducible: true})
+	defer packer.Close()
+
+	desc, err := addDirectory(context.Background(), store, packer, "data", "", dir)
+	if err != nil {
+		t.Fatal("addDirectory() error =", err)
+	}
+	if desc.MediaType != ocispec.MediaTypeImageLayerGzip {
+		t.Errorf("addDirectory() media type = %v, want %v", desc.MediaType, ocispec.MediaTypeImageLayerGzip)
+	}
+	for _, key := range []string{ocispec.AnnotationTitle, file.AnnotationDigest, file.AnnotationUnpack} {
+		if _, ok := desc.Annotations[key]; !ok {
+			t.Errorf("addDirectory() annotation %q is missing", key)
+		}
+	}
+	if got := desc.Annotations[ocispec.AnnotationTitle]; got != "data" {
+		t.Errorf("addDirectory() title = %v, want %v", got, "data")
+	}
+}
+
+func Test_loadFiles_pushSpecInferMediaType(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"model.onnx", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0600); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+	spec, err := decodePushSpec(strings.NewReader(fmt.Sprintf(`
+files:
+  - path: %s
+  - path: %s
+    mediaType: application/vnd.me.notes
+`, filepath.Join(dir, "model.onnx"), filepath.Join(dir, "notes.txt"))))
+	if err != nil {
+		t.Fatal("decodePushSpec() error =", err)
+	}
+	opts := &pushOptions{}
+	if err := applyPushSpec(opts, spec); err != nil {
+		t.Fatal("applyPushSpec() error =", err)
+	}
+	store, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+
+	loadOpts := fileLoadOptions{
+		inferrer: &mediatype.Inferrer{Mapping: map[string]string{".onnx": "application/vnd.onnx"}},
+	}
+	files, err := loadFiles(context.Background(), store, nil, opts.FileRefs, status.NewDiscardHandler(), loadOpts)
+	if err != nil {
+		t.Fatal("loadFiles() error =", err)
+	}
+	want := map[string]string{
+		"model.onnx": "application/vnd.onnx",
+		"notes.txt":  "application/vnd.me.notes",
+	}
+	if len(files) != len(want) {
+		t.Fatalf("loadFiles() loaded %d files, want %d", len(files), len(want))
+	}
+	for _, desc := range files {
+		name := filepath.Base(desc.Annotations[ocispec.AnnotationTitle])
+		if desc.MediaType != want[name] {
+			t.Errorf("loadFiles() media type of %s = %v, want %v", name, desc.MediaType, want[name])
+		}
+	}
+}
+
+func Test_loadFiles_metadata(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh"), 0600); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal("os.Chmod() error =", err)
+	}
+	store, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+
+	files, err := loadFiles(context.Background(), store, nil, []string{path}, status.NewDiscardHandler(), fileLoadOptions{})
+	if err != nil {
+		t.Fatal("loadFiles() error =", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("loadFiles() loaded %d files, want 1", len(files))
+	}
+	if got, want := files[0].Annotations[ofile.AnnotationMode], "0755"; got != want {
+		t.Errorf("loadFiles() annotation %q = %v, want %v", ofile.AnnotationMode, got, want)
+	}
+	if _, ok := files[0].Annotations[ofile.AnnotationModTime]; !ok {
+		t.Errorf("loadFiles() annotation %q is missing", ofile.AnnotationModTime)
+	}
+}
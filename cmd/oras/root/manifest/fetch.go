@@ -64,6 +64,14 @@ Example - Fetch manifest and output metadata encoded in JSON:
 Example - Fetch manifest from a registry with specified media type:
   oras manifest fetch --media-type 'application/vnd.oci.image.manifest.v1+json' localhost:5000/hello:v1
 
+Example - Fetch manifest from the local cache without contacting the registry:
+  export ORAS_CACHE=~/.oras/cache
+  oras manifest fetch --offline localhost:5000/hello:v1
+
+Example - Fetch manifest with the tag resolved from the local cache if cached within 10 minutes:
+  export ORAS_CACHE=~/.oras/cache
+  oras manifest fetch --cache-ttl 10m localhost:5000/hello:v1
+
 Example - Fetch manifest from a registry with certain platform:
   oras manifest fetch --platform 'linux/arm/v5' localhost:5000/hello:v1
 
@@ -106,6 +114,7 @@ Example - Fetch raw manifest from an OCI layout archive file 'layout.tar':
 		option.FormatTypeJSON.WithUsage("Print in prettified JSON format"),
 		option.FormatTypeGoTemplate.WithUsage("Print using the given Go template"),
 	)
+	opts.EnableResolveFlags()
 	option.ApplyFlags(&opts, cmd.Flags())
 	return oerrors.Command(cmd, &opts.Target)
 }
@@ -55,6 +55,10 @@ func fetchConfigCmd() *cobra.Command {
 Example - Fetch the config:
   oras manifest fetch-config localhost:5000/hello:v1
 
+Example - Fetch the config with local cache:
+  export ORAS_CACHE=~/.oras/cache
+  oras manifest fetch-config localhost:5000/hello:v1
+
 Example - Fetch the config of certain platform:
   oras manifest fetch-config --platform 'linux/arm/v5' localhost:5000/hello:v1
 
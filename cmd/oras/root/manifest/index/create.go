@@ -75,6 +75,9 @@ Example - Create an index and push it with multiple tags:
 Example - Create and push an index with annotations:
   oras manifest index create localhost:5000/hello:v1 linux-amd64 --annotation "key=val"
 
+Example - Create and push an index with the revision, source and version annotations filled from the CI environment:
+  oras manifest index create localhost:5000/hello:v1 linux-amd64 --annotation-preset ci
+
 Example - Create an index and push to an OCI image layout folder 'layout-dir' and tag with 'v1':
   oras manifest index create layout-dir:v1 linux-amd64 sha256:99e4703fbf30916f549cd6bfa9cdbab614b5392fbe64fdee971359a77073cdf9
 
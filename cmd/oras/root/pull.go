@@ -17,16 +17,23 @@ package root
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 	"sync"
+	"syscall"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras/cmd/oras/internal/argument"
 	"oras.land/oras/cmd/oras/internal/command"
 	"oras.land/oras/cmd/oras/internal/display"
@@ -35,7 +42,11 @@ import (
 	oerrors "oras.land/oras/cmd/oras/internal/errors"
 	"oras.land/oras/cmd/oras/internal/fileref"
 	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/internal/archive"
 	"oras.land/oras/internal/descriptor"
+	"oras.land/oras/internal/docker"
+	"oras.land/oras/internal/download"
+	ofile "oras.land/oras/internal/file"
 	"oras.land/oras/internal/graph"
 )
 
@@ -46,13 +57,32 @@ type pullOptions struct {
 	option.Target
 	option.Format
 
-	concurrency       int
-	KeepOldFiles      bool
-	IncludeSubject    bool
-	PathTraversal     bool
-	Output            string
-	ManifestConfigRef string
-	verbose           bool
+	concurrency         int
+	segmentThreshold    option.ByteSize
+	resume              bool
+	KeepOldFiles        bool
+	IncludeSubject      bool
+	PathTraversal       bool
+	PreservePermissions bool
+	Output              string
+	ManifestConfigRef   string
+	verbose             bool
+
+	includes          []string
+	excludes          []string
+	mediaTypes        []string
+	annotationFilters []string
+	filter            *layerFilter
+
+	allPlatforms bool
+	sync         bool
+	prune        bool
+	atomic       bool
+	staging      string
+
+	IncludeUnnamed  bool
+	unnamedTemplate string
+	unnamed         *unnamedNamer
 }
 
 func pullCmd() *cobra.Command {
@@ -78,12 +108,54 @@ Example - Pull files from a registry with local cache:
   export ORAS_CACHE=~/.oras/cache
   oras pull localhost:5000/hello:v1
 
+Example - Pull files from a registry with local cache limited to 10 GiB:
+  export ORAS_CACHE=~/.oras/cache ORAS_CACHE_MAX_SIZE=10GiB
+  oras pull localhost:5000/hello:v1
+
+Example - Pull files from the local cache without contacting the registry:
+  export ORAS_CACHE=~/.oras/cache
+  oras pull --offline localhost:5000/hello:v1
+
+Example - Pull files with tags resolved from the local cache if cached within 1 hour:
+  export ORAS_CACHE=~/.oras/cache
+  oras pull --cache-ttl 1h localhost:5000/hello:v1
+
 Example - Pull files from a registry with certain platform:
   oras pull --platform linux/arm/v5 localhost:5000/hello:v1
 
+Example - Pull files of all platforms in an index into subdirectories such as 'linux_amd64' and 'linux_arm64_v8':
+  oras pull --all-platforms localhost:5000/hello:v1
+
 Example - Pull all files with concurrency level tuned:
   oras pull --concurrency 6 localhost:5000/hello:v1
 
+Example - Pull files and keep partially downloaded blobs to resume an interrupted pull later:
+  oras pull --resume localhost:5000/hello:v1
+
+Example - Pull files with blobs larger than 1 GiB downloaded in 6 parallel byte ranges:
+  oras pull --concurrency 6 --segment-threshold 1GiB localhost:5000/hello:v1
+
+Example - Pull files and restore their mode, modification time and symbolic links:
+  oras pull --preserve-permissions localhost:5000/hello:v1
+
+Example - Pull only the ONNX files under the 'models' directory, excluding drafts:
+  oras pull --include "models/**/*.onnx" --exclude "**/draft-*" localhost:5000/hello:v1
+
+Example - Pull only the files of a media type and with an annotation:
+  oras pull --media-type application/vnd.onnx --annotation "stage=prod" localhost:5000/hello:v1
+
+Example - Pull all layers and the config, naming those without file names after their digests:
+  oras pull --include-unnamed localhost:5000/hello:v1
+
+Example - Pull all layers, naming those without file names with a Go template:
+  oras pull --unnamed-template "layers/{{.Digest.Encoded}}.tar.gz" localhost:5000/hello:v1
+
+Example - Pull only the files changed since the last pull into 'out' and remove the files no longer in the artifact:
+  oras pull --prune -o out localhost:5000/hello:v2
+
+Example - Pull files into a staging directory and replace 'out' with it only after all files are pulled:
+  oras pull --atomic -o out localhost:5000/hello:v2
+
 Example - Pull files and format output in JSON:
   oras pull localhost:5000/hello:v1 --format json
 
@@ -99,7 +171,33 @@ Example - Pull artifact files from an OCI layout archive 'layout.tar':
 		Args: oerrors.CheckArgs(argument.Exactly(1), "the artifact reference you want to pull"),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			opts.RawReference = args[0]
-			return option.Parse(cmd, &opts)
+			if err := oerrors.CheckMutuallyExclusiveFlags(cmd.Flags(), "platform", "all-platforms"); err != nil {
+				return err
+			}
+			if err := oerrors.CheckMutuallyExclusiveFlags(cmd.Flags(), "atomic", "keep-old-files"); err != nil {
+				return err
+			}
+			if err := option.Parse(cmd, &opts); err != nil {
+				return err
+			}
+			var err error
+			if opts.filter, err = newLayerFilter(opts.includes, opts.excludes, opts.mediaTypes, opts.annotationFilters); err != nil {
+				return err
+			}
+			if opts.prune {
+				if !cmd.Flags().Changed("output") {
+					// refuse to prune the working directory by default
+					return &oerrors.Error{
+						Err:            errors.New("`--prune` requires the output directory to be specified"),
+						Recommendation: "Specify the directory to prune via `--output`, e.g. `--output .` for the current directory",
+					}
+				}
+				opts.sync = true
+			}
+			if opts.IncludeUnnamed || opts.unnamedTemplate != "" {
+				opts.unnamed, err = newUnnamedNamer(opts.unnamedTemplate)
+			}
+			return err
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Printer.Verbose = opts.verbose
@@ -110,11 +208,25 @@ Example - Pull artifact files from an OCI layout archive 'layout.tar':
 	cmd.Flags().BoolVarP(&opts.KeepOldFiles, "keep-old-files", "k", false, "do not replace existing files when pulling, treat them as errors")
 	cmd.Flags().BoolVarP(&opts.PathTraversal, "allow-path-traversal", "T", false, "allow storing files out of the output directory")
 	cmd.Flags().BoolVarP(&opts.IncludeSubject, "include-subject", "", false, "[Preview] recursively pull the subject of artifacts")
+	cmd.Flags().BoolVarP(&opts.PreservePermissions, "preserve-permissions", "", false, "[Experimental] restore the mode, modification time and symbolic links of files recorded on push")
 	cmd.Flags().StringVarP(&opts.Output, "output", "o", ".", "output directory")
 	cmd.Flags().StringVarP(&opts.ManifestConfigRef, "config", "", "", "output manifest config file")
 	cmd.Flags().IntVarP(&opts.concurrency, "concurrency", "", 3, "concurrency level")
+	cmd.Flags().BoolVarP(&opts.resume, "resume", "", false, "[Experimental] keep partially downloaded blobs beside the output directory and resume them on the next pull, for blobs not read from the local cache")
+	cmd.Flags().Var(&opts.segmentThreshold, "segment-threshold", "[Experimental] download blobs larger than `size` in parallel byte ranges, as many as the concurrency level, implies --resume")
+	cmd.Flags().StringArrayVarP(&opts.includes, "include", "", nil, "[Experimental] only pull files whose names match the glob `pattern`, ** matches any number of directories")
+	cmd.Flags().StringArrayVarP(&opts.excludes, "exclude", "", nil, "[Experimental] do not pull files whose names match the glob `pattern`")
+	cmd.Flags().StringArrayVarP(&opts.mediaTypes, "media-type", "", nil, "[Experimental] only pull files of the media `type`")
+	cmd.Flags().StringArrayVarP(&opts.annotationFilters, "annotation", "", nil, "[Experimental] only pull files with the annotation in the `key=value` format")
+	cmd.Flags().BoolVarP(&opts.sync, "sync", "", false, "[Experimental] only download files missing or changed in the output directory, by comparing the digests of existing files")
+	cmd.Flags().BoolVarP(&opts.prune, "prune", "", false, "[Experimental] remove files in the output directory not in the artifact, implies --sync and requires --output")
+	cmd.Flags().BoolVarP(&opts.atomic, "atomic", "", false, "[Experimental] pull into a staging directory beside the output directory and replace the output directory with it only after all files are pulled and verified")
+	cmd.Flags().BoolVarP(&opts.allPlatforms, "all-platforms", "", false, "[Experimental] pull every platform of an index into the <os>_<arch>[_<variant>] subdirectory of the output directory")
+	cmd.Flags().BoolVarP(&opts.IncludeUnnamed, "include-unnamed", "", false, "[Experimental] pull layers and configs without file names into files named after their digests")
+	cmd.Flags().StringVarP(&opts.unnamedTemplate, "unnamed-template", "", "", "[Experimental] Go `template` for the file names of unnamed layers and configs, executed with their descriptors, implies --include-unnamed (default \""+defaultUnnamedTemplate+"\")")
 	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "print status output for unnamed blobs")
 	opts.SetTypes(option.FormatTypeText, option.FormatTypeJSON, option.FormatTypeGoTemplate)
+	opts.EnableResolveFlags()
 	option.ApplyFlags(&opts, cmd.Flags())
 	return oerrors.Command(cmd, &opts.Target)
 }
@@ -125,6 +237,20 @@ func runPull(cmd *cobra.Command, opts *pullOptions) error {
 	if err != nil {
 		return err
 	}
+	if opts.atomic {
+		if err := checkAtomicOutput(opts.Output); err != nil {
+			return err
+		}
+		// cancel the pull on termination as well as on interrupt, so that
+		// the staging directory is removed
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, syscall.SIGTERM)
+		defer stop()
+		if opts.staging, err = ofile.NewStagingDir(opts.Output); err != nil {
+			return err
+		}
+		defer os.RemoveAll(opts.staging)
+	}
 	// Copy Options
 	copyOptions := oras.DefaultCopyOptions
 	copyOptions.Concurrency = opts.concurrency
@@ -142,26 +268,162 @@ func runPull(cmd *cobra.Command, opts *pullOptions) error {
 	if err != nil {
 		return err
 	}
-	dst, err := file.New(opts.Output)
-	if err != nil {
-		return err
+	// keep partially downloaded files beside the output to resume later, not
+	// in the staging directory which is removed if the pull fails. Blobs read
+	// from the local cache or an OCI layout are written into files directly
+	if repo, ok := src.(*remote.Repository); ok && (opts.resume || opts.segmentThreshold > 0) {
+		src = download.NewTargetWithSegments(src, opts.Output, download.SegmentOptions{
+			Threshold:  int64(opts.segmentThreshold),
+			Count:      opts.concurrency,
+			Repository: repo,
+		})
 	}
-	defer dst.Close()
-	dst.AllowPathTraversalOnWrite = opts.PathTraversal
-	dst.DisableOverwrite = opts.KeepOldFiles
 
-	desc, err := doPull(ctx, src, dst, copyOptions, metadataHandler, statusHandler, opts)
+	var desc ocispec.Descriptor
+	if opts.allPlatforms {
+		desc, err = pullAllPlatforms(ctx, src, copyOptions, metadataHandler, statusHandler, opts)
+	} else {
+		desc, err = pullFiles(ctx, src, copyOptions, metadataHandler, statusHandler, opts)
+	}
 	if err != nil {
 		if errors.Is(err, file.ErrPathTraversalDisallowed) {
 			err = fmt.Errorf("%s: %w", "use flag --allow-path-traversal to allow insecurely pulling files outside of working directory", err)
 		}
 		return err
 	}
+	if opts.atomic {
+		// all files are verified against their digests when pulled
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := ofile.ReplaceDir(opts.staging, opts.Output); err != nil {
+			return fmt.Errorf("failed to replace %s with the pulled files: %w", opts.Output, err)
+		}
+	}
 
 	return metadataHandler.OnCompleted(&opts.Target, desc)
 }
 
-func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget, opts oras.CopyOptions, metadataHandler metadata.PullHandler, statusHandler status.PullHandler, po *pullOptions) (ocispec.Descriptor, error) {
+// outputDir returns the directory the files are written into, which is the
+// staging directory if pulling atomically.
+func (opts *pullOptions) outputDir() string {
+	if opts.staging != "" {
+		return opts.staging
+	}
+	return opts.Output
+}
+
+// checkAtomicOutput returns an error if the output directory cannot be
+// replaced, as it contains the current working directory.
+func checkAtomicOutput(output string) error {
+	output, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(output, wd); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return &oerrors.Error{
+			Err:            fmt.Errorf("cannot replace the output directory %s: it contains the current working directory", output),
+			Recommendation: `Specify another output directory with "--output", or remove "--atomic"`,
+		}
+	}
+	return nil
+}
+
+// pullFiles pulls the files of the artifact referenced by po.Reference into
+// po.Output, or into the staging directory if pulling atomically.
+func pullFiles(ctx context.Context, src oras.ReadOnlyTarget, opts oras.CopyOptions, metadataHandler metadata.PullHandler, statusHandler status.PullHandler, po *pullOptions) (ocispec.Descriptor, error) {
+	dst, err := file.New(po.outputDir())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer dst.Close()
+	dst.AllowPathTraversalOnWrite = po.PathTraversal
+	dst.DisableOverwrite = po.KeepOldFiles
+
+	// directories not compressed by gzip and chunked files are restored
+	// outside the file store
+	unpacker := archive.NewUnpacker(dst, po.outputDir())
+
+	return doPull(ctx, src, unpacker, opts, metadataHandler, statusHandler, po)
+}
+
+// pullAllPlatforms pulls the files of each platform in the index referenced
+// by po.Reference into the subdirectory of po.Output named after the
+// platform. Manifests without platforms or of unknown platforms, such as
+// attestations, are skipped.
+func pullAllPlatforms(ctx context.Context, src oras.ReadOnlyTarget, opts oras.CopyOptions, metadataHandler metadata.PullHandler, statusHandler status.PullHandler, po *pullOptions) (ocispec.Descriptor, error) {
+	root, err := oras.Resolve(ctx, src, po.Reference, oras.DefaultResolveOptions)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if root.MediaType != ocispec.MediaTypeImageIndex && root.MediaType != docker.MediaTypeManifestList {
+		return ocispec.Descriptor{}, &oerrors.Error{
+			Err:            fmt.Errorf("%s is not an index: found media type %q", po.RawReference, root.MediaType),
+			Recommendation: "Remove --all-platforms to pull a single artifact",
+		}
+	}
+	indexBytes, err := content.FetchAll(ctx, src, root)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse index %s: %w", root.Digest, err)
+	}
+
+	pulled := make(map[string]bool)
+	for _, manifest := range index.Manifests {
+		if manifest.Platform == nil || manifest.Platform.OS == "" || manifest.Platform.OS == "unknown" || manifest.Platform.Architecture == "" {
+			if err := statusHandler.OnNodeSkipped(manifest); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			continue
+		}
+		dir := platformDir(*manifest.Platform)
+		if pulled[dir] {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to pull %s: multiple manifests found for the platform directory %q", po.RawReference, dir)
+		}
+		pulled[dir] = true
+
+		platformOpts := *po
+		platformOpts.Output = filepath.Join(po.Output, dir)
+		if po.staging != "" {
+			platformOpts.staging = filepath.Join(po.staging, dir)
+		}
+		platformOpts.Reference = manifest.Digest.String()
+		if _, err := pullFiles(ctx, src, opts, metadataHandler, statusHandler, &platformOpts); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if err := metadataHandler.OnPlatformPulled(*manifest.Platform, platformOpts.Output, manifest, po.Path); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	if po.staging != "" {
+		// keep the files outside the platform directories
+		if err := ofile.LinkTree(po.Output, po.staging, func(name string) bool {
+			return pulled[name]
+		}); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	return root, nil
+}
+
+// platformDir returns the directory name of the platform in the format of
+// <os>_<arch>[_<variant>].
+func platformDir(platform ocispec.Platform) string {
+	dir := platform.OS + "_" + platform.Architecture
+	if platform.Variant != "" {
+		dir += "_" + platform.Variant
+	}
+	return dir
+}
+
+func doPull(ctx context.Context, src oras.ReadOnlyTarget, unpacker *archive.Unpacker, opts oras.CopyOptions, metadataHandler metadata.PullHandler, statusHandler status.PullHandler, po *pullOptions) (ocispec.Descriptor, error) {
 	var configPath, configMediaType string
 	var err error
 
@@ -171,14 +433,20 @@ func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget,
 			return ocispec.Descriptor{}, err
 		}
 	}
-	dst, stopTrack, err := statusHandler.TrackTarget(dst)
+	dst, stopTrack, err := statusHandler.TrackTarget(unpacker)
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
 	defer func() {
 		_ = stopTrack()
 	}()
+	var syncer *fileSyncer
+	if po.sync {
+		syncer = newFileSyncer(po.Output, po.staging)
+		syncer.addName(configPath)
+	}
 	var printed sync.Map
+	var pulled sync.Map // map[string]map[string]string, file name to annotations
 	var getConfigOnce sync.Once
 	opts.FindSuccessors = func(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
 		statusFetcher := content.FetcherFunc(func(ctx context.Context, target ocispec.Descriptor) (fetched io.ReadCloser, fetchErr error) {
@@ -204,6 +472,20 @@ func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget,
 		if err != nil {
 			return nil, err
 		}
+		// skip the layers not selected by the filters without fetching them
+		if po.filter != nil {
+			var selected []ocispec.Descriptor
+			for _, s := range nodes {
+				if !po.filter.selected(s) {
+					if err := notifyOnce(&printed, s, statusHandler.OnNodeSkipped); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				selected = append(selected, s)
+			}
+			nodes = selected
+		}
 		if subject != nil && po.IncludeSubject {
 			nodes = append(nodes, *subject)
 		}
@@ -221,14 +503,41 @@ func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget,
 			}
 		}
 
+		// skip the files unchanged in the output directory
+		if syncer != nil {
+			var changed []ocispec.Descriptor
+			for _, s := range nodes {
+				unchanged, err := syncer.isUnchanged(s)
+				if err != nil {
+					return nil, err
+				}
+				if unchanged {
+					if err := notifyOnce(&printed, s, statusHandler.OnNodeSkipped); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				changed = append(changed, s)
+			}
+			nodes = changed
+		}
+
+		// chunks are assembled into files while being pulled
+		if err := unpacker.RegisterChunks(nodes); err != nil {
+			return nil, err
+		}
 		var ret []ocispec.Descriptor
 		for _, s := range nodes {
+			if archive.IsChunk(s) {
+				ret = append(ret, s)
+				continue
+			}
 			if s.Annotations[ocispec.AnnotationTitle] == "" {
 				if content.Equal(s, ocispec.DescriptorEmptyJSON) {
 					// empty layer
 					continue
 				}
-				if s.Annotations[ocispec.AnnotationTitle] == "" {
+				if po.unnamed == nil {
 					// unnamed layers are skipped
 					if err = metadataHandler.OnLayerSkipped(s); err != nil {
 						return nil, err
@@ -239,6 +548,26 @@ func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget,
 					return nil, err
 				}
 				if len(ss) == 0 {
+					if po.unnamed != nil {
+						// name the unnamed blob to pull it as a file
+						named, err := po.unnamed.name(s)
+						if err != nil {
+							return nil, err
+						}
+						if po.filter.selected(named) {
+							po.unnamed.record(named)
+							unchanged := false
+							if syncer != nil {
+								if unchanged, err = syncer.isUnchanged(named); err != nil {
+									return nil, err
+								}
+							}
+							if !unchanged {
+								ret = append(ret, named)
+								continue
+							}
+						}
+					}
 					// skip s if it is unnamed AND has no successors.
 					if err := notifyOnce(&printed, s, statusHandler.OnNodeSkipped); err != nil {
 						return nil, err
@@ -261,7 +590,41 @@ func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget,
 			return err
 		}
 		for _, s := range successors {
+			if syncer != nil {
+				// record the names of all files in the artifact, including
+				// the ones not selected, to keep them from being pruned
+				name := s.Annotations[ocispec.AnnotationTitle]
+				if archive.IsChunk(s) {
+					name = s.Annotations[archive.AnnotationChunkTitle]
+				} else if unnamed, ok := po.unnamed.lookup(s.Digest); ok && name == "" {
+					name = unnamed
+				}
+				syncer.addName(name)
+			}
+			if !po.filter.selected(s) {
+				continue
+			}
+			if archive.IsChunk(s) && s.Annotations[archive.AnnotationChunkIndex] == "0" {
+				// report the file assembled from the chunks
+				assembled, err := archive.AssembledDescriptor(s)
+				if err != nil {
+					return err
+				}
+				if err = metadataHandler.OnFilePulled(assembled.Annotations[ocispec.AnnotationTitle], po.Output, assembled, po.Path); err != nil {
+					return err
+				}
+				pulled.Store(assembled.Annotations[ocispec.AnnotationTitle], s.Annotations)
+				continue
+			}
+			if name, ok := po.unnamed.lookup(s.Digest); ok && s.Annotations[ocispec.AnnotationTitle] == "" {
+				// report the unnamed blob pulled as a file
+				if err = metadataHandler.OnFilePulled(name, po.Output, s, po.Path); err != nil {
+					return err
+				}
+				continue
+			}
 			if name, ok := s.Annotations[ocispec.AnnotationTitle]; ok {
+				pulled.Store(name, s.Annotations)
 				if err = metadataHandler.OnFilePulled(name, po.Output, s, po.Path); err != nil {
 					return err
 				}
@@ -276,7 +639,56 @@ func doPull(ctx context.Context, src oras.ReadOnlyTarget, dst oras.GraphTarget,
 
 	// Copy
 	desc, err := oras.Copy(ctx, src, po.Reference, dst, po.Reference, opts)
-	return desc, err
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if po.staging != "" {
+		// keep the other files in the output directory, as pulling without
+		// --atomic does
+		if err := ofile.LinkTree(po.Output, po.staging, nil); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	if po.PreservePermissions {
+		if err := restoreMetadata(&pulled, po); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	if po.prune {
+		removed, err := syncer.prune()
+		for _, path := range removed {
+			if err := metadataHandler.OnFileRemoved(path); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+		}
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	return desc, nil
+}
+
+// restoreMetadata restores the metadata recorded on push to the pulled files.
+func restoreMetadata(pulled *sync.Map, po *pullOptions) error {
+	var restoreErr error
+	pulled.Range(func(key, value any) bool {
+		name := key.(string)
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(po.outputDir(), name)
+			if po.staging != "" {
+				// the files unchanged in the output directory are linked
+				// into the staging directory, and must not be modified
+				// before replacing the output directory
+				if restoreErr = ofile.UnshareFile(path, filepath.Join(po.Output, name)); restoreErr != nil {
+					return false
+				}
+			}
+		}
+		restoreErr = ofile.RestoreMetadata(po.outputDir(), path, value.(map[string]string), po.PathTraversal)
+		return restoreErr == nil
+	})
+	return restoreErr
 }
 
 func notifyOnce(notified *sync.Map, s ocispec.Descriptor, notify func(ocispec.Descriptor) error) error {
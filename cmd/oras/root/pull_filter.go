@@ -0,0 +1,108 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	oerrors "oras.land/oras/cmd/oras/internal/errors"
+	"oras.land/oras/internal/archive"
+	"oras.land/oras/internal/file"
+)
+
+// layerFilter selects the named layers to be pulled. Layers without names are
+// not filtered.
+type layerFilter struct {
+	// include matches the names of the layers to be pulled, nil to match all.
+	include *file.Matcher
+	// exclude matches the names of the layers not to be pulled, nil to match
+	// none.
+	exclude *file.Matcher
+	// mediaTypes lists the media types of the layers to be pulled, empty to
+	// match all.
+	mediaTypes []string
+	// annotations lists the annotations the layers to be pulled must have.
+	annotations map[string]string
+}
+
+// newLayerFilter creates a filter from the include and exclude patterns, the
+// media types and the key=value annotation filters. Returns nil if no filter
+// is specified.
+func newLayerFilter(includes, excludes, mediaTypes, annotations []string) (*layerFilter, error) {
+	if len(includes) == 0 && len(excludes) == 0 && len(mediaTypes) == 0 && len(annotations) == 0 {
+		return nil, nil
+	}
+	f := &layerFilter{
+		mediaTypes: mediaTypes,
+	}
+	var err error
+	if len(includes) > 0 {
+		if f.include, err = file.NewMatcher(includes); err != nil {
+			return nil, err
+		}
+	}
+	if len(excludes) > 0 {
+		if f.exclude, err = file.NewMatcher(excludes); err != nil {
+			return nil, err
+		}
+	}
+	if len(annotations) > 0 {
+		f.annotations = make(map[string]string)
+		for _, anno := range annotations {
+			key, val, ok := strings.Cut(anno, "=")
+			if !ok || key == "" {
+				return nil, &oerrors.Error{
+					Err:            fmt.Errorf("invalid annotation filter %q", anno),
+					Recommendation: `Please use the correct format in the flag: --annotation "key=value"`,
+				}
+			}
+			f.annotations[key] = val
+		}
+	}
+	return f, nil
+}
+
+// selected returns true if the layer described by desc is to be pulled.
+func (f *layerFilter) selected(desc ocispec.Descriptor) bool {
+	if f == nil {
+		return true
+	}
+	name := desc.Annotations[ocispec.AnnotationTitle]
+	if archive.IsChunk(desc) {
+		name = desc.Annotations[archive.AnnotationChunkTitle]
+	}
+	if name == "" {
+		return true
+	}
+	if f.include != nil && !f.include.Match(name) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.Match(name) {
+		return false
+	}
+	if len(f.mediaTypes) > 0 && !slices.Contains(f.mediaTypes, desc.MediaType) {
+		return false
+	}
+	for key, val := range f.annotations {
+		if v, ok := desc.Annotations[key]; !ok || v != val {
+			return false
+		}
+	}
+	return true
+}
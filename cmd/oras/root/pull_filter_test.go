@@ -0,0 +1,85 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/internal/archive"
+)
+
+func Test_layerFilter_selected(t *testing.T) {
+	layer := func(name, mediaType string, annotations map[string]string) ocispec.Descriptor {
+		desc := ocispec.Descriptor{
+			MediaType:   mediaType,
+			Annotations: map[string]string{ocispec.AnnotationTitle: name},
+		}
+		for k, v := range annotations {
+			desc.Annotations[k] = v
+		}
+		return desc
+	}
+	tests := []struct {
+		name        string
+		includes    []string
+		excludes    []string
+		mediaTypes  []string
+		annotations []string
+		desc        ocispec.Descriptor
+		want        bool
+	}{
+		{name: "no filter", desc: layer("a.onnx", "", nil), want: true},
+		{name: "included", includes: []string{"models/**"}, desc: layer("models/a.onnx", "", nil), want: true},
+		{name: "not included", includes: []string{"models/**"}, desc: layer("a.onnx", "", nil), want: false},
+		{name: "excluded", includes: []string{"models/**"}, excludes: []string{"**/*.tmp"}, desc: layer("models/a.tmp", "", nil), want: false},
+		{name: "media type matched", mediaTypes: []string{"foo", "bar"}, desc: layer("a", "bar", nil), want: true},
+		{name: "media type not matched", mediaTypes: []string{"foo"}, desc: layer("a", "bar", nil), want: false},
+		{name: "annotation matched", annotations: []string{"stage=prod"}, desc: layer("a", "", map[string]string{"stage": "prod"}), want: true},
+		{name: "annotation not matched", annotations: []string{"stage=prod"}, desc: layer("a", "", map[string]string{"stage": "dev"}), want: false},
+		{name: "annotation missing", annotations: []string{"stage="}, desc: layer("a", "", nil), want: false},
+		{name: "unnamed", includes: []string{"models/**"}, desc: ocispec.Descriptor{MediaType: "foo"}, want: true},
+		{
+			name:     "chunk",
+			includes: []string{"*.bin"},
+			desc: ocispec.Descriptor{
+				MediaType:   ocispec.MediaTypeImageLayer,
+				Annotations: map[string]string{archive.AnnotationChunkTitle: "model.bin"},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newLayerFilter(tt.includes, tt.excludes, tt.mediaTypes, tt.annotations)
+			if err != nil {
+				t.Fatal("newLayerFilter() error =", err)
+			}
+			if got := f.selected(tt.desc); got != tt.want {
+				t.Errorf("layerFilter.selected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newLayerFilter_invalid(t *testing.T) {
+	if _, err := newLayerFilter([]string{"[invalid"}, nil, nil, nil); err == nil {
+		t.Error("newLayerFilter() error = nil, want error for invalid pattern")
+	}
+	if _, err := newLayerFilter(nil, nil, nil, []string{"invalid"}); err == nil {
+		t.Error("newLayerFilter() error = nil, want error for invalid annotation filter")
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras/internal/archive"
+	"oras.land/oras/internal/download"
+	ofile "oras.land/oras/internal/file"
+)
+
+// fileSyncer skips pulling the files already present in the output directory
+// with matching digests, and records the names of the files in the artifact
+// for pruning stale files.
+type fileSyncer struct {
+	output    string
+	staging   string
+	unchanged sync.Map // map[string]bool, file name to whether it is unchanged
+	names     sync.Map // map[string]bool, names of the files in the artifact
+}
+
+// newFileSyncer creates a syncer for the output directory. If staging is not
+// empty, stale files are pruned from the staging directory instead, which
+// replaces the output directory later.
+func newFileSyncer(output string, staging string) *fileSyncer {
+	return &fileSyncer{output: output, staging: staging}
+}
+
+// isUnchanged returns true if the file of the layer desc exists in the output
+// directory with the same content. Directories are always pulled.
+func (s *fileSyncer) isUnchanged(desc ocispec.Descriptor) (bool, error) {
+	if desc.Annotations[file.AnnotationUnpack] == "true" {
+		return false, nil
+	}
+	name := desc.Annotations[ocispec.AnnotationTitle]
+	if archive.IsChunk(desc) {
+		// compare with the file assembled from the chunks
+		assembled, err := archive.AssembledDescriptor(desc)
+		if err != nil {
+			return false, err
+		}
+		name, desc = assembled.Annotations[ocispec.AnnotationTitle], assembled
+	}
+	if name == "" {
+		return false, nil
+	}
+	if unchanged, ok := s.unchanged.Load(name); ok {
+		return unchanged.(bool), nil
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.output, name)
+	}
+	unchanged, err := ofile.Matches(path, desc)
+	if err != nil {
+		return false, err
+	}
+	s.unchanged.Store(name, unchanged)
+	return unchanged, nil
+}
+
+// addName records the name of a file in the artifact.
+func (s *fileSyncer) addName(name string) {
+	if name != "" && !filepath.IsAbs(name) {
+		s.names.Store(path.Clean(filepath.ToSlash(name)), true)
+	}
+}
+
+// prune removes the files in the output directory not in the artifact, and
+// returns the paths of the removed files in the output directory. The partial
+// downloads are kept.
+func (s *fileSyncer) prune() ([]string, error) {
+	root := s.output
+	if s.staging != "" {
+		root = s.staging
+	}
+	removed, err := ofile.Prune(root, func(name string) bool {
+		if _, ok := s.names.Load(name); ok {
+			return true
+		}
+		return isPartialFile(name)
+	})
+	if s.staging != "" {
+		for i, path := range removed {
+			if rel, relErr := filepath.Rel(s.staging, path); relErr == nil {
+				removed[i] = filepath.Join(s.output, rel)
+			}
+		}
+	}
+	return removed, err
+}
+
+// isPartialFile returns true if name is a file kept in the output directory
+// for resuming a partial download.
+func isPartialFile(name string) bool {
+	return !strings.Contains(name, "/") && download.IsPartialName(name)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+)
+
+func Test_fileSyncer_isUnchanged(t *testing.T) {
+	output := t.TempDir()
+	if err := os.WriteFile(filepath.Join(output, "foo"), []byte("foo"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	named := func(name string, data string, annotations ...string) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes("test", []byte(data))
+		desc.Annotations = map[string]string{ocispec.AnnotationTitle: name}
+		for i := 0; i+1 < len(annotations); i += 2 {
+			desc.Annotations[annotations[i]] = annotations[i+1]
+		}
+		return desc
+	}
+	tests := []struct {
+		name string
+		desc ocispec.Descriptor
+		want bool
+	}{
+		{name: "unchanged", desc: named("foo", "foo"), want: true},
+		{name: "changed", desc: named("foo", "bar"), want: false},
+		{name: "missing", desc: named("bar", "bar"), want: false},
+		{name: "directory", desc: named("foo", "foo", file.AnnotationUnpack, "true"), want: false},
+		{name: "unnamed", desc: content.NewDescriptorFromBytes("test", []byte("foo")), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFileSyncer(output, "")
+			got, err := s.isUnchanged(tt.desc)
+			if err != nil {
+				t.Fatal("fileSyncer.isUnchanged() error =", err)
+			}
+			if got != tt.want {
+				t.Errorf("fileSyncer.isUnchanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fileSyncer_prune(t *testing.T) {
+	output := t.TempDir()
+	for _, name := range []string{"foo", "stale", ".0123.partial", "sub/.0123.partial"} {
+		path := filepath.Join(output, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+	s := newFileSyncer(output, "")
+	s.addName("./foo")
+	removed, err := s.prune()
+	if err != nil {
+		t.Fatal("fileSyncer.prune() error =", err)
+	}
+	want := []string{filepath.Join(output, "stale"), filepath.Join(output, "sub", ".0123.partial")}
+	if len(removed) != len(want) || removed[0] != want[0] || removed[1] != want[1] {
+		t.Errorf("fileSyncer.prune() removed %v, want %v", removed, want)
+	}
+}
+
+func Test_fileSyncer_prune_staging(t *testing.T) {
+	output := t.TempDir()
+	staging := t.TempDir()
+	for _, name := range []string{"foo", "stale"} {
+		if err := os.WriteFile(filepath.Join(staging, name), nil, 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+	s := newFileSyncer(output, staging)
+	s.addName("foo")
+	removed, err := s.prune()
+	if err != nil {
+		t.Fatal("fileSyncer.prune() error =", err)
+	}
+	if want := filepath.Join(output, "stale"); len(removed) != 1 || removed[0] != want {
+		t.Errorf("fileSyncer.prune() removed %v, want %v", removed, []string{want})
+	}
+	if _, err := os.Stat(filepath.Join(staging, "stale")); !os.IsNotExist(err) {
+		t.Errorf("fileSyncer.prune() did not remove the stale file in the staging directory, error = %v", err)
+	}
+}
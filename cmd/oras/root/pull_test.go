@@ -17,8 +17,10 @@ package root
 
 import (
 	"context"
+	"os"
 	"testing"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"oras.land/oras/cmd/oras/internal/errors"
 	"oras.land/oras/cmd/oras/internal/option"
@@ -41,3 +43,43 @@ func Test_runPull_errType(t *testing.T) {
 		t.Fatalf("got %v, want %v", got, want)
 	}
 }
+
+func Test_platformDir(t *testing.T) {
+	tests := []struct {
+		platform ocispec.Platform
+		want     string
+	}{
+		{platform: ocispec.Platform{OS: "linux", Architecture: "amd64"}, want: "linux_amd64"},
+		{platform: ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}, want: "linux_arm64_v8"},
+	}
+	for _, tt := range tests {
+		if got := platformDir(tt.platform); got != tt.want {
+			t.Errorf("platformDir() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func Test_checkAtomicOutput(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal("os.Getwd() error =", err)
+	}
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: ".", wantErr: true},
+		{output: "..", wantErr: true},
+		{output: wd, wantErr: true},
+		{output: "out", wantErr: false},
+		{output: "../out", wantErr: false},
+		{output: "..out", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if err := checkAtomicOutput(tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("checkAtomicOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
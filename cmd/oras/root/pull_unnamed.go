@@ -0,0 +1,91 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	oerrors "oras.land/oras/cmd/oras/internal/errors"
+)
+
+// defaultUnnamedTemplate names unnamed blobs after their digests.
+const defaultUnnamedTemplate = "{{.Digest.Algorithm}}-{{.Digest.Encoded}}"
+
+// unnamedNamer names the unnamed layers and configs to be pulled.
+type unnamedNamer struct {
+	tmpl  *template.Template
+	names sync.Map // map[digest.Digest]string
+}
+
+// newUnnamedNamer creates a namer rendering file names with the Go template
+// text, which is executed with the descriptor of the blob.
+func newUnnamedNamer(text string) (*unnamedNamer, error) {
+	if text == "" {
+		text = defaultUnnamedTemplate
+	}
+	tmpl, err := template.New("unnamed").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, &oerrors.Error{
+			Err:            fmt.Errorf("invalid name template %q: %w", text, err),
+			Recommendation: `Please use a Go template with the fields of the descriptor, e.g. "{{.Digest.Encoded}}.tar.gz"`,
+		}
+	}
+	return &unnamedNamer{tmpl: tmpl}, nil
+}
+
+// name returns a copy of desc named by the template.
+func (n *unnamedNamer) name(desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	var sb strings.Builder
+	if err := n.tmpl.Execute(&sb, desc); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to name the unnamed blob %s: %w", desc.Digest, err)
+	}
+	name := sb.String()
+	if name == "" || path.Clean(name) == "." {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to name the unnamed blob %s: empty file name %q", desc.Digest, name)
+	}
+
+	named := desc
+	named.Annotations = maps.Clone(desc.Annotations)
+	if named.Annotations == nil {
+		named.Annotations = make(map[string]string)
+	}
+	named.Annotations[ocispec.AnnotationTitle] = name
+	return named, nil
+}
+
+// record records the name of the blob named by name for lookup.
+func (n *unnamedNamer) record(named ocispec.Descriptor) {
+	n.names.Store(named.Digest, named.Annotations[ocispec.AnnotationTitle])
+}
+
+// lookup returns the name assigned to the blob of dgst.
+func (n *unnamedNamer) lookup(dgst digest.Digest) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	name, ok := n.names.Load(dgst)
+	if !ok {
+		return "", false
+	}
+	return name.(string), true
+}
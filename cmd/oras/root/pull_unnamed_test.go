@@ -0,0 +1,85 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_unnamedNamer(t *testing.T) {
+	desc := content.NewDescriptorFromBytes("application/vnd.foo", []byte("foo"))
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "default", want: "sha256-" + desc.Digest.Encoded()},
+		{name: "template", template: "blobs/{{.Digest.Encoded}}.bin", want: "blobs/" + desc.Digest.Encoded() + ".bin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := newUnnamedNamer(tt.template)
+			if err != nil {
+				t.Fatal("newUnnamedNamer() error =", err)
+			}
+			named, err := n.name(desc)
+			if err != nil {
+				t.Fatal("unnamedNamer.name() error =", err)
+			}
+			if got := named.Annotations[ocispec.AnnotationTitle]; got != tt.want {
+				t.Errorf("unnamedNamer.name() = %v, want %v", got, tt.want)
+			}
+			if desc.Annotations != nil {
+				t.Errorf("unnamedNamer.name() modified the annotations of the original descriptor")
+			}
+			if _, ok := n.lookup(desc.Digest); ok {
+				t.Errorf("unnamedNamer.lookup() found the name before recorded")
+			}
+			n.record(named)
+			if got, ok := n.lookup(desc.Digest); !ok || got != tt.want {
+				t.Errorf("unnamedNamer.lookup() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unnamedNamer_invalid(t *testing.T) {
+	if _, err := newUnnamedNamer("{{.Digest"); err == nil {
+		t.Error("newUnnamedNamer() error = nil, want error for invalid template")
+	}
+	n, err := newUnnamedNamer("{{.Annotations.missing}}")
+	if err != nil {
+		t.Fatal("newUnnamedNamer() error =", err)
+	}
+	if _, err := n.name(ocispec.Descriptor{Annotations: map[string]string{}}); err == nil {
+		t.Error("unnamedNamer.name() error = nil, want error for missing key")
+	}
+	n, err = newUnnamedNamer("{{if false}}x{{end}}")
+	if err != nil {
+		t.Fatal("newUnnamedNamer() error =", err)
+	}
+	if _, err := n.name(ocispec.Descriptor{}); err == nil {
+		t.Error("unnamedNamer.name() error = nil, want error for empty name")
+	}
+
+	var nilNamer *unnamedNamer
+	if _, ok := nilNamer.lookup("sha256:foo"); ok {
+		t.Error("unnamedNamer.lookup() on nil namer found a name")
+	}
+}
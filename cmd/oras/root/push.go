@@ -19,7 +19,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
 	"strings"
+	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
@@ -35,22 +38,28 @@ import (
 	oerrors "oras.land/oras/cmd/oras/internal/errors"
 	"oras.land/oras/cmd/oras/internal/fileref"
 	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/internal/archive"
 	"oras.land/oras/internal/contentutil"
 	"oras.land/oras/internal/listener"
 	"oras.land/oras/internal/registryutil"
 )
 
 type pushOptions struct {
+	option.Cache
 	option.Common
 	option.Packer
 	option.ArtifactPlatform
 	option.ImageSpec
 	option.Target
 	option.Format
+	option.Upload
 
 	extraRefs         []string
 	manifestConfigRef string
 	artifactType      string
+	fromFile          string
+	subject           string
+	splitSize         option.ByteSize
 	concurrency       int
 	verbose           bool
 }
@@ -104,6 +113,9 @@ Example - Push repository with manifest annotations:
 Example - Push repository with manifest annotation file:
   oras push --annotation-file annotation.json localhost:5000/hello:v1
 
+Example - Push file "hi.txt" with the creation time, revision, source and version annotations filled from the clock and the git repository:
+  oras push --annotation-preset created,git localhost:5000/hello:v1 hi.txt
+
 Example - Push artifact to repository with platform:
   oras push --artifact-platform linux/arm/v5 localhost:5000/hello:v1
 
@@ -113,6 +125,52 @@ Example - Push file "hi.txt" with multiple tags:
 Example - Push file "hi.txt" with multiple tags and concurrency level tuned:
   oras push --concurrency 6 localhost:5000/hello:tag1,tag2,tag3 hi.txt
 
+Example - Push large file "model.bin" in chunks of 64 MiB and resume the upload if interrupted:
+  oras push --chunk-size 64MiB localhost:5000/hello:v1 model.bin
+
+Example - Push directory "dist" reproducibly, using the commit time as the timestamp of all entries:
+  export SOURCE_DATE_EPOCH=$(git log -1 --format=%ct)
+  oras push --reproducible localhost:5000/hello:v1 dist
+
+Example - Push directory "dist" as a zstd compressed layer with compression level 19:
+  oras push --compression zstd --compression-level 19 localhost:5000/hello:v1 dist
+
+Example - Push all "*.onnx" files under "models" except those under "models/tmp", with the media type "application/x-onnx":
+  oras push --exclude "models/tmp" localhost:5000/hello:v1 "models/**/*.onnx:application/x-onnx"
+
+Example - Push files listed in "files.txt", one <file>[:<type>] entry per line:
+  oras push --files-from files.txt localhost:5000/hello:v1
+
+Example - Push files found by "find", separated by NUL characters:
+  find . -name "*.txt" -print0 | oras push --files-from - localhost:5000/hello:v1
+
+Example - Push files with media types inferred from their extensions and content, e.g. "application/json" for "config.json":
+  oras push --infer-media-type localhost:5000/hello:v1 config.json module.wasm
+
+Example - Push files with media types inferred from the mapping file "media-types.yaml", e.g. '".onnx": application/vnd.onnx':
+  oras push --media-type-map media-types.yaml localhost:5000/hello:v1 model.onnx
+
+Example - Push the artifact described by "artifact.yaml":
+  cat <<EOF > artifact.yaml
+  artifactType: application/vnd.example+type
+  tags: [v1, latest]
+  annotations:
+    key: val
+  files:
+    - path: hi.txt
+      mediaType: application/vnd.me.hi
+      annotations:
+        lang: en
+  EOF
+  oras push --from-file artifact.yaml localhost:5000/hello
+
+Example - Push file "model.bin" as chunk layers of at most 2 GiB each, which are reassembled by "oras pull":
+  oras push --split-size 2GiB localhost:5000/hello:v1 model.bin
+
+Example - Push file "hi.txt" and store it in the local cache for later pulls:
+  export ORAS_CACHE=~/.oras/cache
+  oras push --populate-cache localhost:5000/hello:v1 hi.txt
+
 Example - Push file "hi.txt" into an OCI image layout folder 'layout-dir' with tag 'test':
   oras push --oci-layout layout-dir:test hi.txt
 `,
@@ -122,6 +180,11 @@ Example - Push file "hi.txt" into an OCI image layout folder 'layout-dir' with t
 			opts.RawReference = refs[0]
 			opts.extraRefs = refs[1:]
 			opts.FileRefs = args[1:]
+			if opts.fromFile != "" {
+				if err := opts.loadSpec(cmd); err != nil {
+					return err
+				}
+			}
 			if err := option.Parse(cmd, &opts); err != nil {
 				return err
 			}
@@ -150,6 +213,12 @@ Example - Push file "hi.txt" into an OCI image layout folder 'layout-dir' with t
 				if opts.manifestConfigRef != "" && opts.artifactType != "" {
 					return errors.New("--artifact-type and --config cannot both be provided for 1.0 OCI image")
 				}
+				if opts.subject != "" {
+					return &oerrors.Error{
+						Err:            errors.New("subject is not supported for OCI image-spec v1.0"),
+						Recommendation: "consider using image spec v1.1 or remove the subject",
+					}
+				}
 			case oras.PackManifestVersion1_1:
 				if opts.manifestConfigRef == "" && opts.artifactType == "" {
 					opts.artifactType = oras.MediaTypeUnknownArtifact
@@ -164,13 +233,40 @@ Example - Push file "hi.txt" into an OCI image layout folder 'layout-dir' with t
 	}
 	cmd.Flags().StringVarP(&opts.manifestConfigRef, "config", "", "", "`path` of image config file")
 	cmd.Flags().StringVarP(&opts.artifactType, "artifact-type", "", "", "artifact type")
+	cmd.Flags().StringVarP(&opts.fromFile, "from-file", "", "", "[Experimental] `path` of the YAML or JSON file describing the artifact to push")
 	cmd.Flags().IntVarP(&opts.concurrency, "concurrency", "", 5, "concurrency level")
+	cmd.Flags().VarP(&opts.splitSize, "split-size", "", "[Experimental] split files larger than the `size` into ordered chunk layers, which are reassembled on pull, e.g. 2GiB")
 	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "print status output for unnamed blobs")
 	opts.SetTypes(option.FormatTypeText, option.FormatTypeJSON, option.FormatTypeGoTemplate)
+	opts.EnablePopulateFlag()
 	option.ApplyFlags(&opts, cmd.Flags())
 	return oerrors.Command(cmd, &opts.Target)
 }
 
+// loadSpec loads the artifact description from the file specified by
+// --from-file.
+func (opts *pushOptions) loadSpec(cmd *cobra.Command) error {
+	for _, flag := range []string{"config", "artifact-type", "annotation", "annotation-file", "artifact-platform"} {
+		if err := oerrors.CheckMutuallyExclusiveFlags(cmd.Flags(), "from-file", flag); err != nil {
+			return err
+		}
+	}
+	if len(opts.FileRefs) != 0 {
+		return &oerrors.Error{
+			Err:            errors.New("files cannot be specified as arguments when using --from-file"),
+			Recommendation: "list the files in the file specified by --from-file instead",
+		}
+	}
+	spec, err := loadPushSpec(opts.fromFile)
+	if err != nil {
+		return &oerrors.Error{
+			Err:            fmt.Errorf("invalid artifact file %s: %w", opts.fromFile, err),
+			Recommendation: `Please check the artifact file. Run "oras push -h" for an example`,
+		}
+	}
+	return applyPushSpec(opts, spec)
+}
+
 func runPush(cmd *cobra.Command, opts *pushOptions) error {
 	ctx, logger := command.GetLogger(cmd, &opts.Common)
 
@@ -179,11 +275,24 @@ func runPush(cmd *cobra.Command, opts *pushOptions) error {
 		ConfigAnnotations:   opts.Annotations[option.AnnotationConfig],
 		ManifestAnnotations: opts.Annotations[option.AnnotationManifest],
 	}
+	if !opts.SourceDateEpoch.IsZero() {
+		// use the source date epoch as the creation time for reproducibility
+		if _, ok := packOpts.ManifestAnnotations[ocispec.AnnotationCreated]; !ok {
+			annotations := maps.Clone(packOpts.ManifestAnnotations)
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[ocispec.AnnotationCreated] = opts.SourceDateEpoch.Format(time.RFC3339)
+			packOpts.ManifestAnnotations = annotations
+		}
+	}
 	store, err := file.New("")
 	if err != nil {
 		return err
 	}
 	defer store.Close()
+	packer := opts.NewArchivePacker()
+	defer packer.Close()
 	if opts.manifestConfigRef != "" {
 		path, cfgMediaType, err := fileref.Parse(opts.manifestConfigRef, oras.MediaTypeUnknownConfig)
 		if err != nil {
@@ -215,12 +324,22 @@ func runPush(cmd *cobra.Command, opts *pushOptions) error {
 		packOpts.ConfigDescriptor = &desc
 	}
 	memoryStore := memory.New()
+	loadOpts := fileLoadOptions{
+		packer:   packer,
+		inferrer: opts.NewMediaTypeInferrer(),
+		metadata: opts.NewMetadataOptions(),
+	}
 	union := contentutil.MultiReadOnlyTarget(memoryStore, store)
+	if opts.splitSize > 0 {
+		loadOpts.chunks = archive.NewChunkStore()
+		loadOpts.splitSize = int64(opts.splitSize)
+		union = contentutil.MultiReadOnlyTarget(memoryStore, store, loadOpts.chunks)
+	}
 	displayStatus, displayMetadata, err := display.NewPushHandler(opts.Printer, opts.Format, opts.TTY, union)
 	if err != nil {
 		return err
 	}
-	descs, err := loadFiles(ctx, store, opts.Annotations, opts.FileRefs, displayStatus)
+	descs, err := loadFiles(ctx, store, opts.Annotations, opts.FileRefs, displayStatus, loadOpts)
 	if err != nil {
 		return err
 	}
@@ -241,7 +360,18 @@ func runPush(cmd *cobra.Command, opts *pushOptions) error {
 	if err != nil {
 		return err
 	}
-	dst, stopTrack, err := displayStatus.TrackTarget(originalDst)
+	if opts.subject != "" {
+		subject, err := oras.Resolve(ctx, originalDst, opts.subject, oras.DefaultResolveOptions)
+		if err != nil {
+			return fmt.Errorf("failed to resolve subject %s: %w", opts.subject, err)
+		}
+		packOpts.Subject = &subject
+	}
+	resumableDst, err := opts.ResumableTarget(originalDst)
+	if err != nil {
+		return err
+	}
+	dst, stopTrack, err := displayStatus.TrackTarget(resumableDst)
 	if err != nil {
 		return err
 	}
@@ -291,6 +421,14 @@ func runPush(cmd *cobra.Command, opts *pushOptions) error {
 		return err
 	}
 
+	// Populate cache
+	populateOpts := oras.DefaultCopyGraphOptions
+	populateOpts.Concurrency = opts.concurrency
+	tags := append([]string{opts.Reference}, opts.extraRefs...)
+	if err := opts.Populate(ctx, union, originalDst, root, tags, populateOpts); err != nil {
+		return err
+	}
+
 	// Export manifest
 	return opts.ExportManifest(ctx, memoryStore, root)
 }
@@ -0,0 +1,128 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2"
+	"oras.land/oras/cmd/oras/internal/option"
+)
+
+// pushSpec describes an artifact to be pushed, in YAML or JSON format.
+// Paths of files are relative to the current working directory.
+type pushSpec struct {
+	ArtifactType string            `yaml:"artifactType"`
+	Tags         []string          `yaml:"tags"`
+	Config       *pushSpecFile     `yaml:"config"`
+	Platform     string            `yaml:"platform"`
+	Subject      string            `yaml:"subject"`
+	Annotations  map[string]string `yaml:"annotations"`
+	Files        []pushSpecFile    `yaml:"files"`
+}
+
+// pushSpecFile describes a file of the artifact.
+type pushSpecFile struct {
+	Path        string            `yaml:"path"`
+	MediaType   string            `yaml:"mediaType"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// loadPushSpec loads the push spec from the file at path.
+func loadPushSpec(path string) (*pushSpec, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return decodePushSpec(fp)
+}
+
+// decodePushSpec decodes a push spec in YAML or JSON format from r.
+func decodePushSpec(r io.Reader) (*pushSpec, error) {
+	var spec pushSpec
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if spec.Config != nil && spec.Config.Path == "" {
+		return nil, errors.New("missing path of the config")
+	}
+	if spec.Config != nil && spec.Platform != "" {
+		return nil, errors.New("config and platform cannot be used at the same time")
+	}
+	names := make(map[string]bool)
+	for i, f := range spec.Files {
+		if f.Path == "" {
+			return nil, fmt.Errorf("missing path of file #%d", i+1)
+		}
+		if names[f.Path] {
+			return nil, fmt.Errorf("duplicated file %q", f.Path)
+		}
+		names[f.Path] = true
+	}
+	return &spec, nil
+}
+
+// applyPushSpec applies spec to the push options.
+func applyPushSpec(opts *pushOptions, spec *pushSpec) error {
+	opts.artifactType = spec.ArtifactType
+	opts.extraRefs = append(opts.extraRefs, spec.Tags...)
+	opts.subject = spec.Subject
+
+	annotations := make(map[string]map[string]string)
+	if len(spec.Annotations) > 0 {
+		annotations[option.AnnotationManifest] = spec.Annotations
+	}
+	if spec.Config != nil {
+		mediaType := spec.Config.MediaType
+		if mediaType == "" {
+			mediaType = oras.MediaTypeUnknownConfig
+		}
+		opts.manifestConfigRef = fileRef(spec.Config.Path, mediaType)
+		if len(spec.Config.Annotations) > 0 {
+			annotations[option.AnnotationConfig] = spec.Config.Annotations
+		}
+	}
+	opts.FileRefs = nil
+	for _, f := range spec.Files {
+		opts.FileRefs = append(opts.FileRefs, fileRef(f.Path, f.MediaType))
+		if len(f.Annotations) > 0 {
+			annotations[f.Path] = f.Annotations
+		}
+	}
+	opts.Annotations = annotations
+
+	if spec.Platform != "" {
+		platform, err := option.ParsePlatform(spec.Platform)
+		if err != nil {
+			return err
+		}
+		opts.Platform.Platform = platform
+	}
+	return nil
+}
+
+// fileRef formats a file reference in the form of <file>:<type>, which is
+// unambiguous for files with colons in their names.
+func fileRef(path, mediaType string) string {
+	return path + ":" + mediaType
+}
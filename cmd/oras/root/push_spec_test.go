@@ -0,0 +1,127 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"oras.land/oras/cmd/oras/internal/option"
+)
+
+func Test_decodePushSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			spec: `
+artifactType: application/vnd.example+type
+tags: [v1]
+config:
+  path: config.json
+files:
+  - path: hi.txt
+    mediaType: application/vnd.me.hi
+`,
+		},
+		{
+			name: "json",
+			spec: `{"artifactType":"application/vnd.example+type","files":[{"path":"hi.txt"}]}`,
+		},
+		{
+			name: "empty",
+			spec: "",
+		},
+		{
+			name:    "unknown field",
+			spec:    "artifact-type: application/vnd.example+type",
+			wantErr: true,
+		},
+		{
+			name:    "missing file path",
+			spec:    "files: [{mediaType: application/vnd.me.hi}]",
+			wantErr: true,
+		},
+		{
+			name:    "duplicated files",
+			spec:    "files: [{path: hi.txt}, {path: hi.txt}]",
+			wantErr: true,
+		},
+		{
+			name:    "config and platform",
+			spec:    "{config: {path: config.json}, platform: linux/amd64}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePushSpec(strings.NewReader(tt.spec)); (err != nil) != tt.wantErr {
+				t.Errorf("decodePushSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_applyPushSpec(t *testing.T) {
+	spec, err := decodePushSpec(strings.NewReader(`
+artifactType: application/vnd.example+type
+tags: [v2, latest]
+subject: v1
+platform: linux/arm64
+annotations:
+  key: val
+files:
+  - path: hi:txt
+    annotations:
+      lang: en
+  - path: bye.txt
+    mediaType: application/vnd.me.bye
+`))
+	if err != nil {
+		t.Fatal("decodePushSpec() error =", err)
+	}
+	opts := &pushOptions{extraRefs: []string{"v1"}}
+	if err := applyPushSpec(opts, spec); err != nil {
+		t.Fatal("applyPushSpec() error =", err)
+	}
+
+	if want := "application/vnd.example+type"; opts.artifactType != want {
+		t.Errorf("artifactType = %v, want %v", opts.artifactType, want)
+	}
+	if want := []string{"v1", "v2", "latest"}; !reflect.DeepEqual(opts.extraRefs, want) {
+		t.Errorf("extraRefs = %v, want %v", opts.extraRefs, want)
+	}
+	if want := "v1"; opts.subject != want {
+		t.Errorf("subject = %v, want %v", opts.subject, want)
+	}
+	if p := opts.Platform.Platform; p == nil || p.OS != "linux" || p.Architecture != "arm64" {
+		t.Errorf("platform = %v, want linux/arm64", p)
+	}
+	if want := []string{"hi:txt:", "bye.txt:application/vnd.me.bye"}; !reflect.DeepEqual(opts.FileRefs, want) {
+		t.Errorf("FileRefs = %v, want %v", opts.FileRefs, want)
+	}
+	wantAnnotations := map[string]map[string]string{
+		option.AnnotationManifest: {"key": "val"},
+		"hi:txt":                  {"lang": "en"},
+	}
+	if !reflect.DeepEqual(opts.Annotations, wantAnnotations) {
+		t.Errorf("Annotations = %v, want %v", opts.Annotations, wantAnnotations)
+	}
+}
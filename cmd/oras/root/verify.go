@@ -0,0 +1,243 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras/cmd/oras/internal/argument"
+	"oras.land/oras/cmd/oras/internal/command"
+	"oras.land/oras/cmd/oras/internal/display"
+	oerrors "oras.land/oras/cmd/oras/internal/errors"
+	"oras.land/oras/cmd/oras/internal/option"
+	"oras.land/oras/internal/archive"
+	"oras.land/oras/internal/docker"
+	ofile "oras.land/oras/internal/file"
+)
+
+type verifyOptions struct {
+	option.Cache
+	option.Common
+	option.Platform
+	option.Target
+	option.Format
+
+	dir string
+}
+
+func verifyCmd() *cobra.Command {
+	var opts verifyOptions
+	cmd := &cobra.Command{
+		Use:   "verify [flags] <name>{:<tag>|@<digest>} <dir>",
+		Short: "[Experimental] Verify the files in a directory against an artifact",
+		Long: `[Experimental] Verify the files in a directory against an artifact
+
+Files and directories named in the artifact are compared with the directory by
+digest and size, and symbolic links by their targets. Missing, modified and
+extra files are reported. Layers without file names, layers with absolute file
+names or names outside the directory, and the partial downloads kept by pull
+are ignored.
+
+Example - Verify that the directory 'out' matches the files of an artifact:
+  oras verify localhost:5000/hello:v1 out
+
+Example - Verify the directory 'out' against the artifact of a certain platform:
+  oras verify --platform linux/arm64 localhost:5000/hello:v1 out
+
+Example - Verify the directory 'out' and format output in JSON:
+  oras verify --format json localhost:5000/hello:v1 out
+
+Example - Verify the directory 'out' and print the modified files with Go template:
+  oras verify --format go-template='{{range .modified}}{{println .}}{{end}}' localhost:5000/hello:v1 out
+
+Example - Verify the directory 'out' against an artifact in an OCI image layout folder 'layout-dir':
+  oras verify --oci-layout layout-dir:v1 out
+`,
+		Args: oerrors.CheckArgs(argument.Exactly(2), "the artifact reference and the directory to verify"),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			opts.RawReference = args[0]
+			opts.dir = args[1]
+			return option.Parse(cmd, &opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVerify(cmd, &opts)
+		},
+	}
+
+	opts.SetTypes(option.FormatTypeText, option.FormatTypeJSON, option.FormatTypeGoTemplate)
+	opts.EnableResolveFlags()
+	option.ApplyFlags(&opts, cmd.Flags())
+	return oerrors.Command(cmd, &opts.Target)
+}
+
+func runVerify(cmd *cobra.Command, opts *verifyOptions) error {
+	ctx, logger := command.GetLogger(cmd, &opts.Common)
+	handler, err := display.NewVerifyHandler(opts.Printer, opts.Format, opts.Path)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(opts.dir); err != nil {
+		return fmt.Errorf("failed to verify %s: %w", opts.dir, err)
+	} else if !fi.IsDir() {
+		return fmt.Errorf("failed to verify %s: not a directory", opts.dir)
+	}
+	target, err := opts.NewReadonlyTarget(ctx, opts.Common, logger)
+	if err != nil {
+		return err
+	}
+	if err := opts.EnsureReferenceNotEmpty(cmd, true); err != nil {
+		return err
+	}
+	src, err := opts.CachedTarget(target)
+	if err != nil {
+		return err
+	}
+
+	fetchOpts := oras.DefaultFetchBytesOptions
+	fetchOpts.TargetPlatform = opts.Platform.Platform
+	desc, manifestBytes, err := oras.FetchBytes(ctx, src, opts.Reference, fetchOpts)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the content of %q: %w", opts.RawReference, err)
+	}
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, docker.MediaTypeManifest:
+	case ocispec.MediaTypeImageIndex, docker.MediaTypeManifestList:
+		return &oerrors.Error{
+			Err:            fmt.Errorf("%s is an index", opts.RawReference),
+			Recommendation: "Use --platform to verify against the artifact of a platform in the index",
+		}
+	default:
+		return fmt.Errorf("%s is not a manifest: found media type %q", opts.RawReference, desc.MediaType)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest %s: %w", desc.Digest, err)
+	}
+
+	entries, err := expectedEntries(ctx, src, manifest.Layers)
+	if err != nil {
+		return err
+	}
+	diffs, err := ofile.Compare(opts.dir, entries, isPartialFile)
+	if err != nil {
+		return err
+	}
+	for _, diff := range diffs {
+		if err := handler.OnDifference(filepath.Join(opts.dir, filepath.FromSlash(diff.Name)), diff.Status); err != nil {
+			return err
+		}
+	}
+	if err := handler.OnCompleted(&opts.Target, desc, opts.dir); err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		return &oerrors.Error{
+			Err:            fmt.Errorf("%d files in %s differ from %s", len(diffs), opts.dir, opts.RawReference),
+			Recommendation: "Pull the artifact again to restore the missing and modified files",
+		}
+	}
+	return nil
+}
+
+// expectedEntries returns the entries expected on disk for the named layers,
+// as they are pulled. Directories are listed by reading their tarballs, and
+// symbolic links are expected as recorded in their annotations. Layers without
+// file names or named outside the directory are skipped.
+func expectedEntries(ctx context.Context, fetcher content.Fetcher, layers []ocispec.Descriptor) ([]ofile.Entry, error) {
+	var entries []ofile.Entry
+	for _, layer := range layers {
+		if archive.IsChunk(layer) {
+			if layer.Annotations[archive.AnnotationChunkIndex] != "0" {
+				continue
+			}
+			// compare with the file assembled from the chunks
+			assembled, err := archive.AssembledDescriptor(layer)
+			if err != nil {
+				return nil, err
+			}
+			layer = assembled
+		}
+		name := layer.Annotations[ocispec.AnnotationTitle]
+		if name == "" || !filepath.IsLocal(name) {
+			continue
+		}
+		if target, ok := layer.Annotations[ofile.AnnotationSymlink]; ok {
+			// symbolic links are restored from the annotation on pull
+			entries = append(entries, ofile.Entry{
+				Name:     filepath.ToSlash(name),
+				Type:     fs.ModeSymlink,
+				Linkname: target,
+			})
+			continue
+		}
+		if layer.Annotations[file.AnnotationUnpack] != "true" {
+			entries = append(entries, ofile.Entry{
+				Name:   filepath.ToSlash(name),
+				Digest: layer.Digest,
+				Size:   layer.Size,
+			})
+			continue
+		}
+		listed, err := listDirectory(ctx, fetcher, filepath.ToSlash(name), layer)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, listed...)
+	}
+	return entries, nil
+}
+
+// listDirectory fetches and lists the tarball of the directory named name.
+func listDirectory(ctx context.Context, fetcher content.Fetcher, name string, desc ocispec.Descriptor) (entries []ofile.Entry, err error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	vr := content.NewVerifyReader(rc, desc)
+	dr, _, err := archive.Decompress(vr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		closeErr := dr.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	if entries, err = archive.ListTar(name, dr); err != nil {
+		return nil, fmt.Errorf("failed to list directory %s: %w", name, err)
+	}
+	// consume the padding after the end of the tar archive
+	if _, err := io.Copy(io.Discard, dr); err != nil {
+		return nil, err
+	}
+	if err := vr.Verify(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
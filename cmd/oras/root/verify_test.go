@@ -0,0 +1,109 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras/internal/archive"
+	ofile "oras.land/oras/internal/file"
+)
+
+func Test_expectedEntries(t *testing.T) {
+	// directory layer
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, header := range []*tar.Header{
+		{Name: "dir/", Typeflag: tar.TypeDir},
+		{Name: "dir/a", Typeflag: tar.TypeReg, Size: 3},
+	} {
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal("tar.Writer.WriteHeader() error =", err)
+		}
+	}
+	if _, err := tw.Write([]byte("foo")); err != nil {
+		t.Fatal("tar.Writer.Write() error =", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("tar.Writer.Close() error =", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal("gzip.Writer.Close() error =", err)
+	}
+	dirLayer := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayerGzip, buf.Bytes())
+	dirLayer.Annotations = map[string]string{
+		ocispec.AnnotationTitle: "dir",
+		file.AnnotationUnpack:   "true",
+	}
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Push(ctx, dirLayer, bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal("memory.Store.Push() error =", err)
+	}
+
+	fileDigest := digest.FromString("file")
+	chunkedDigest := digest.FromString("chunked")
+	chunk := func(index string) ocispec.Descriptor {
+		return ocispec.Descriptor{
+			MediaType: "application/octet-stream",
+			Digest:    digest.FromString(index),
+			Size:      1,
+			Annotations: map[string]string{
+				archive.AnnotationChunkTitle:  "chunked",
+				archive.AnnotationChunkIndex:  index,
+				archive.AnnotationChunkCount:  "2",
+				archive.AnnotationChunkDigest: chunkedDigest.String(),
+				archive.AnnotationChunkSize:   "7",
+			},
+		}
+	}
+	layers := []ocispec.Descriptor{
+		{Digest: fileDigest, Size: 4, Annotations: map[string]string{ocispec.AnnotationTitle: "file"}},
+		{Digest: digest.FromString("unnamed"), Size: 7},
+		{Digest: digest.FromString("outside"), Size: 7, Annotations: map[string]string{ocispec.AnnotationTitle: "../outside"}},
+		{Digest: digest.FromString("absolute"), Size: 8, Annotations: map[string]string{ocispec.AnnotationTitle: "/absolute"}},
+		{Digest: digest.FromString(""), Annotations: map[string]string{ocispec.AnnotationTitle: "link", ofile.AnnotationSymlink: "file"}},
+		chunk("0"),
+		chunk("1"),
+		dirLayer,
+	}
+	got, err := expectedEntries(ctx, store, layers)
+	if err != nil {
+		t.Fatal("expectedEntries() error =", err)
+	}
+	want := []ofile.Entry{
+		{Name: "file", Digest: fileDigest, Size: 4},
+		{Name: "link", Type: fs.ModeSymlink, Linkname: "file"},
+		{Name: "chunked", Digest: chunkedDigest, Size: 7},
+		{Name: "dir", Type: fs.ModeDir},
+		{Name: "dir/a", Digest: digest.FromString("foo"), Size: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expectedEntries() = %v, want %v", got, want)
+	}
+}
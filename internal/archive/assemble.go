@@ -0,0 +1,249 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// chunkedFile is a file assembled from chunks.
+type chunkedFile struct {
+	lock      sync.Mutex
+	name      string
+	digest    digest.Digest
+	size      int64
+	path      string
+	remaining int
+}
+
+// chunkCopy is a position of a chunk in an assembled file.
+type chunkCopy struct {
+	file   *chunkedFile
+	offset int64
+	size   int64
+}
+
+// RegisterChunks registers the chunks in layers to be assembled into files.
+// Chunks must be registered before being pushed.
+func (u *Unpacker) RegisterChunks(layers []ocispec.Descriptor) error {
+	groups := make(map[string][]ocispec.Descriptor)
+	for _, layer := range layers {
+		if IsChunk(layer) {
+			name := layer.Annotations[AnnotationChunkTitle]
+			groups[name] = append(groups[name], layer)
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	u.chunkLock.Lock()
+	defer u.chunkLock.Unlock()
+	if u.chunkFiles == nil {
+		u.chunkFiles = make(map[string]*chunkedFile)
+		u.chunkCopies = make(map[digest.Digest][]chunkCopy)
+	}
+	for name, chunks := range groups {
+		assembled, err := AssembledDescriptor(chunks[0])
+		if err != nil {
+			return err
+		}
+		if f, ok := u.chunkFiles[name]; ok {
+			if f.digest != assembled.Digest {
+				return fmt.Errorf("%s: chunks of different files found", name)
+			}
+			// already registered by another manifest
+			continue
+		}
+		copies, err := layoutChunks(name, assembled, chunks)
+		if err != nil {
+			return err
+		}
+		f := &chunkedFile{
+			name:      name,
+			digest:    assembled.Digest,
+			size:      assembled.Size,
+			remaining: len(chunks),
+		}
+		u.chunkFiles[name] = f
+		for i, chunk := range chunks {
+			copies[i].file = f
+			u.chunkCopies[chunk.Digest] = append(u.chunkCopies[chunk.Digest], copies[i])
+		}
+	}
+	return nil
+}
+
+// layoutChunks sorts chunks by index in place, validates them against the
+// assembled file and returns their positions.
+func layoutChunks(name string, assembled ocispec.Descriptor, chunks []ocispec.Descriptor) ([]chunkCopy, error) {
+	for _, chunk := range chunks {
+		if _, err := strconv.Atoi(chunk.Annotations[AnnotationChunkIndex]); err != nil {
+			return nil, fmt.Errorf("%s: invalid chunk index %q", name, chunk.Annotations[AnnotationChunkIndex])
+		}
+		count, err := strconv.Atoi(chunk.Annotations[AnnotationChunkCount])
+		if err != nil || count != len(chunks) {
+			return nil, fmt.Errorf("%s: expecting %s chunks, got %d", name, chunk.Annotations[AnnotationChunkCount], len(chunks))
+		}
+		if chunk.Annotations[AnnotationChunkDigest] != assembled.Digest.String() || chunk.Annotations[AnnotationChunkSize] != strconv.FormatInt(assembled.Size, 10) {
+			return nil, fmt.Errorf("%s: chunks of different files found", name)
+		}
+	}
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunkIndex(chunks[i]) < chunkIndex(chunks[j])
+	})
+
+	copies := make([]chunkCopy, len(chunks))
+	var offset int64
+	for i, chunk := range chunks {
+		if chunkIndex(chunk) != i {
+			return nil, fmt.Errorf("%s: missing or duplicated chunk #%d", name, i)
+		}
+		copies[i] = chunkCopy{offset: offset, size: chunk.Size}
+		offset += chunk.Size
+	}
+	if offset != assembled.Size {
+		return nil, fmt.Errorf("%s: size of chunks %d does not match file size %d", name, offset, assembled.Size)
+	}
+	return copies, nil
+}
+
+// chunkIndex returns the index of a validated chunk.
+func chunkIndex(chunk ocispec.Descriptor) int {
+	index, _ := strconv.Atoi(chunk.Annotations[AnnotationChunkIndex])
+	return index
+}
+
+// assemble writes the chunk to all its positions in the assembled files, and
+// verifies the files once all their chunks are written.
+func (u *Unpacker) assemble(expected ocispec.Descriptor, r io.Reader) error {
+	u.chunkLock.Lock()
+	copies := u.chunkCopies[expected.Digest]
+	u.chunkLock.Unlock()
+	if len(copies) == 0 {
+		return fmt.Errorf("%s: chunk %s is not registered", expected.Annotations[AnnotationChunkTitle], expected.Digest)
+	}
+
+	// write the first copy from the content
+	first := copies[0]
+	if err := first.file.prepare(u); err != nil {
+		return err
+	}
+	vr := content.NewVerifyReader(r, expected)
+	if err := writeAt(first.file.path, first.offset, vr); err != nil {
+		return err
+	}
+	if err := vr.Verify(); err != nil {
+		return fmt.Errorf("%s: %w", first.file.name, err)
+	}
+	// write duplicated chunks from the first copy
+	for _, c := range copies[1:] {
+		if err := c.file.prepare(u); err != nil {
+			return err
+		}
+		src, err := os.Open(first.file.path)
+		if err != nil {
+			return err
+		}
+		err = writeAt(c.file.path, c.offset, io.NewSectionReader(src, first.offset, first.size))
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range copies {
+		if err := c.file.complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepare creates the assembled file on the first call.
+func (f *chunkedFile) prepare(u *Unpacker) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.path != "" {
+		return nil
+	}
+	path, err := u.resolveWritePath(f.name)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path for writing: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("failed to ensure directories of the target path: %w", err)
+	}
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fp.Close(); err != nil {
+		return err
+	}
+	f.path = path
+	return nil
+}
+
+// complete marks a chunk as written, and verifies the file if all the chunks
+// are written.
+func (f *chunkedFile) complete() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.remaining--
+	if f.remaining > 0 {
+		return nil
+	}
+	fp, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	verifier := f.digest.Verifier()
+	n, err := io.Copy(verifier, fp)
+	if err != nil {
+		return err
+	}
+	if n != f.size || !verifier.Verified() {
+		return fmt.Errorf("%s: %w", f.name, content.ErrMismatchedDigest)
+	}
+	return nil
+}
+
+// writeAt writes the content read from r into the file at path from offset.
+func writeAt(path string, offset int64, r io.Reader) (err error) {
+	fp, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := fp.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	_, err = io.Copy(io.NewOffsetWriter(fp, offset), r)
+	return err
+}
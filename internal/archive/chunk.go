@@ -0,0 +1,182 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// Annotations of the chunk layers split from a file.
+const (
+	// AnnotationChunkTitle is the title of the file split into chunks.
+	AnnotationChunkTitle = "land.oras.chunk.title"
+	// AnnotationChunkIndex is the zero-based index of the chunk.
+	AnnotationChunkIndex = "land.oras.chunk.index"
+	// AnnotationChunkCount is the number of chunks of the file.
+	AnnotationChunkCount = "land.oras.chunk.count"
+	// AnnotationChunkDigest is the digest of the whole file.
+	AnnotationChunkDigest = "land.oras.chunk.digest"
+	// AnnotationChunkSize is the size of the whole file.
+	AnnotationChunkSize = "land.oras.chunk.size"
+)
+
+// IsChunk returns true if desc describes a chunk split from a file.
+func IsChunk(desc ocispec.Descriptor) bool {
+	return desc.Annotations[AnnotationChunkTitle] != ""
+}
+
+// AssembledDescriptor returns the descriptor of the whole file that the chunk
+// described by desc is split from.
+func AssembledDescriptor(desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	dgst, err := digest.Parse(desc.Annotations[AnnotationChunkDigest])
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("invalid chunk %s: %w", desc.Digest, err)
+	}
+	size, err := strconv.ParseInt(desc.Annotations[AnnotationChunkSize], 10, 64)
+	if err != nil || size < 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("invalid chunk %s: invalid size %q", desc.Digest, desc.Annotations[AnnotationChunkSize])
+	}
+	return ocispec.Descriptor{
+		MediaType: desc.MediaType,
+		Digest:    dgst,
+		Size:      size,
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle: desc.Annotations[AnnotationChunkTitle],
+		},
+	}, nil
+}
+
+// section is a byte range of a file.
+type section struct {
+	path   string
+	offset int64
+	size   int64
+}
+
+// ChunkStore is a read-only target serving the chunks of split files from the
+// files directly.
+type ChunkStore struct {
+	lock     sync.RWMutex
+	sections map[digest.Digest]section
+}
+
+// NewChunkStore creates an empty chunk store.
+func NewChunkStore() *ChunkStore {
+	return &ChunkStore{
+		sections: make(map[digest.Digest]section),
+	}
+}
+
+// Split splits the file at path into ordered chunks of at most chunkSize
+// bytes, and returns the descriptors of the chunks annotated with name.
+func (s *ChunkStore) Split(path, name, mediaType string, chunkSize int64) ([]ocispec.Descriptor, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", chunkSize)
+	}
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	fi, err := fp.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// hash the chunks and the whole file in one pass
+	fileDigester := digest.Canonical.Digester()
+	var chunks []ocispec.Descriptor
+	var sections []section
+	if fi.Size() == 0 {
+		return nil, fmt.Errorf("%s: cannot split an empty file", path)
+	}
+	for offset := int64(0); offset < fi.Size(); offset += chunkSize {
+		size := min(chunkSize, fi.Size()-offset)
+		chunkDigester := digest.Canonical.Digester()
+		n, err := io.Copy(io.MultiWriter(chunkDigester.Hash(), fileDigester.Hash()), io.NewSectionReader(fp, offset, size))
+		if err != nil {
+			return nil, err
+		}
+		if n != size {
+			return nil, fmt.Errorf("%s: %w", path, io.ErrUnexpectedEOF)
+		}
+		chunks = append(chunks, ocispec.Descriptor{
+			MediaType: mediaType,
+			Digest:    chunkDigester.Digest(),
+			Size:      size,
+		})
+		sections = append(sections, section{path: path, offset: offset, size: size})
+	}
+
+	fileDigest := fileDigester.Digest().String()
+	count := strconv.Itoa(len(chunks))
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i := range chunks {
+		chunks[i].Annotations = map[string]string{
+			AnnotationChunkTitle:  name,
+			AnnotationChunkIndex:  strconv.Itoa(i),
+			AnnotationChunkCount:  count,
+			AnnotationChunkDigest: fileDigest,
+			AnnotationChunkSize:   strconv.FormatInt(fi.Size(), 10),
+		}
+		s.sections[chunks[i].Digest] = sections[i]
+	}
+	return chunks, nil
+}
+
+// Fetch fetches the chunk described by target.
+func (s *ChunkStore) Fetch(_ context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	s.lock.RLock()
+	sec, ok := s.sections[target.Digest]
+	s.lock.RUnlock()
+	if !ok || sec.size != target.Size {
+		return nil, fmt.Errorf("%s: %w", target.Digest, errdef.ErrNotFound)
+	}
+	fp, err := os.Open(sec.path)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.NewSectionReader(fp, sec.offset, sec.size),
+		Closer: fp,
+	}, nil
+}
+
+// Exists returns true if the chunk described by target exists.
+func (s *ChunkStore) Exists(_ context.Context, target ocispec.Descriptor) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	sec, ok := s.sections[target.Digest]
+	return ok && sec.size == target.Size, nil
+}
+
+// Resolve always returns ErrNotFound since chunks are not tagged.
+func (s *ChunkStore) Resolve(_ context.Context, ref string) (ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, fmt.Errorf("%s: %w", ref, errdef.ErrNotFound)
+}
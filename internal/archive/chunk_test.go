@@ -0,0 +1,167 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+)
+
+// splitTestFile splits a file of the given content into chunks of chunkSize.
+func splitTestFile(t *testing.T, data []byte, chunkSize int64) (*ChunkStore, []ocispec.Descriptor) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model.bin")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	s := NewChunkStore()
+	chunks, err := s.Split(path, "model.bin", ocispec.MediaTypeImageLayer, chunkSize)
+	if err != nil {
+		t.Fatal("ChunkStore.Split() error =", err)
+	}
+	return s, chunks
+}
+
+func TestChunkStore_Split(t *testing.T) {
+	data := []byte("0123456789abcdefghij!")
+	s, chunks := splitTestFile(t, data, 10)
+	if len(chunks) != 3 {
+		t.Fatalf("ChunkStore.Split() got %d chunks, want 3", len(chunks))
+	}
+
+	ctx := context.Background()
+	var got []byte
+	for i, chunk := range chunks {
+		if want := data[i*10 : min(len(data), (i+1)*10)]; chunk.Digest != digest.FromBytes(want) {
+			t.Errorf("chunk #%d digest = %v, want %v", i, chunk.Digest, digest.FromBytes(want))
+		}
+		blob, err := content.FetchAll(ctx, s, chunk)
+		if err != nil {
+			t.Fatal("content.FetchAll() error =", err)
+		}
+		got = append(got, blob...)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("fetched chunks = %q, want %q", got, data)
+	}
+
+	assembled, err := AssembledDescriptor(chunks[2])
+	if err != nil {
+		t.Fatal("AssembledDescriptor() error =", err)
+	}
+	if assembled.Digest != digest.FromBytes(data) || assembled.Size != int64(len(data)) {
+		t.Errorf("AssembledDescriptor() = %v, want digest %v and size %d", assembled, digest.FromBytes(data), len(data))
+	}
+	if got := assembled.Annotations[ocispec.AnnotationTitle]; got != "model.bin" {
+		t.Errorf("AssembledDescriptor() title = %v, want %v", got, "model.bin")
+	}
+}
+
+func TestUnpacker_assemble(t *testing.T) {
+	// the first and the last chunks are identical
+	data := []byte("aaaaabbbbbaaaaa")
+	s, chunks := splitTestFile(t, data, 5)
+	dir := t.TempDir()
+	store, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+	u := NewUnpacker(store, dir)
+	if err := u.RegisterChunks(chunks); err != nil {
+		t.Fatal("Unpacker.RegisterChunks() error =", err)
+	}
+
+	// push out of order and skip the duplicated chunk
+	ctx := context.Background()
+	for _, chunk := range []ocispec.Descriptor{chunks[1], chunks[0]} {
+		rc, err := s.Fetch(ctx, chunk)
+		if err != nil {
+			t.Fatal("ChunkStore.Fetch() error =", err)
+		}
+		err = u.Push(ctx, chunk, rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal("Unpacker.Push() error =", err)
+		}
+		if exists, err := u.Exists(ctx, chunk); err != nil || !exists {
+			t.Errorf("Unpacker.Exists() = %v, %v, want true", exists, err)
+		}
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "model.bin"))
+	if err != nil {
+		t.Fatal("os.ReadFile() error =", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("assembled file = %q, want %q", got, data)
+	}
+}
+
+func TestUnpacker_assemble_mismatchedDigest(t *testing.T) {
+	_, chunks := splitTestFile(t, []byte("0123456789"), 5)
+	chunks[0].Annotations[AnnotationChunkDigest] = digest.FromString("foo").String()
+	chunks[1].Annotations[AnnotationChunkDigest] = digest.FromString("foo").String()
+	dir := t.TempDir()
+	store, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+	u := NewUnpacker(store, dir)
+	if err := u.RegisterChunks(chunks); err != nil {
+		t.Fatal("Unpacker.RegisterChunks() error =", err)
+	}
+
+	ctx := context.Background()
+	if err := u.Push(ctx, chunks[0], bytes.NewReader([]byte("01234"))); err != nil {
+		t.Fatal("Unpacker.Push() error =", err)
+	}
+	if err := u.Push(ctx, chunks[1], bytes.NewReader([]byte("56789"))); err == nil {
+		t.Error("Unpacker.Push() error = nil, want digest mismatch")
+	}
+}
+
+func TestUnpacker_RegisterChunks_invalid(t *testing.T) {
+	_, chunks := splitTestFile(t, []byte("0123456789"), 4)
+	tests := []struct {
+		name   string
+		layers []ocispec.Descriptor
+	}{
+		{name: "missing chunk", layers: []ocispec.Descriptor{chunks[0], chunks[2]}},
+		{name: "duplicated chunk", layers: []ocispec.Descriptor{chunks[0], chunks[1], chunks[1]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &Unpacker{WorkingDir: t.TempDir()}
+			if err := u.RegisterChunks(tt.layers); err == nil {
+				t.Error("Unpacker.RegisterChunks() error = nil, want error")
+			}
+		})
+	}
+
+	u := &Unpacker{WorkingDir: t.TempDir()}
+	if err := u.Push(context.Background(), chunks[0], bytes.NewReader(nil)); err == nil {
+		t.Error("Unpacker.Push() error = nil, want error for unregistered chunk")
+	}
+}
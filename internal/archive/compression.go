@@ -0,0 +1,172 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Compression is the compression algorithm of packed directories.
+type Compression string
+
+// Supported compression algorithms.
+const (
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+	CompressionNone Compression = "none"
+)
+
+// MediaTypeImageLayerZstd is the media type of zstd compressed layers.
+const MediaTypeImageLayerZstd = "application/vnd.oci.image.layer.v1.tar+zstd"
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ParseCompression parses the name of a compression algorithm.
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(name); c {
+	case CompressionGzip, CompressionZstd, CompressionNone:
+		return c, nil
+	case "":
+		return CompressionGzip, nil
+	default:
+		return "", fmt.Errorf("unsupported compression %q: expecting one of gzip, zstd or none", name)
+	}
+}
+
+// MediaType returns the default media type of layers compressed by c.
+func (c Compression) MediaType() string {
+	switch c {
+	case CompressionZstd:
+		return MediaTypeImageLayerZstd
+	case CompressionNone:
+		return ocispec.MediaTypeImageLayer
+	default:
+		return ocispec.MediaTypeImageLayerGzip
+	}
+}
+
+// ValidateLevel validates the compression level for c, where 0 stands for
+// the default level.
+func (c Compression) ValidateLevel(level int) error {
+	var minLevel, maxLevel int
+	switch c {
+	case CompressionNone:
+		maxLevel = 0
+	case CompressionZstd:
+		minLevel, maxLevel = 1, 22
+	default:
+		minLevel, maxLevel = gzip.BestSpeed, gzip.BestCompression
+	}
+	if level != 0 && (level < minLevel || level > maxLevel) {
+		if maxLevel == 0 {
+			return fmt.Errorf("compression level is not applicable to compression %q", c)
+		}
+		return fmt.Errorf("invalid compression level %d for %s: expecting a value between %d and %d", level, c, minLevel, maxLevel)
+	}
+	return nil
+}
+
+// compress returns a writer compressing data written to w. The output is
+// deterministic if reproducible is set.
+func compress(w io.Writer, c Compression, level int, reproducible bool) (io.WriteCloser, error) {
+	switch c {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionZstd:
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		return zstd.NewWriter(w, opts...)
+	default:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		gzw, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		if reproducible {
+			// deterministic gzip header
+			gzw.Header = gzip.Header{OS: 255}
+		}
+		return gzw, nil
+	}
+}
+
+// Decompress returns a reader decompressing r, detecting the compression
+// algorithm from the leading magic bytes. Uncompressed content is returned
+// as is.
+func Decompress(r io.Reader) (io.ReadCloser, Compression, error) {
+	br := bufio.NewReader(r)
+	c, err := detectCompression(br)
+	if err != nil {
+		return nil, "", err
+	}
+	switch c {
+	case CompressionGzip:
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, "", err
+		}
+		return gzr, c, nil
+	case CompressionZstd:
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, "", err
+		}
+		return zr.IOReadCloser(), c, nil
+	default:
+		return io.NopCloser(br), c, nil
+	}
+}
+
+// detectCompression detects the compression algorithm from the leading magic
+// bytes without consuming them.
+func detectCompression(br *bufio.Reader) (Compression, error) {
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return CompressionGzip, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		return CompressionZstd, nil
+	default:
+		return CompressionNone, nil
+	}
+}
+
+// nopWriteCloser wraps a writer with a no-op Close method.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing.
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDecompress(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world "), 1024)
+	for _, c := range []Compression{CompressionGzip, CompressionZstd, CompressionNone} {
+		t.Run(string(c), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := compress(&buf, c, 0, true)
+			if err != nil {
+				t.Fatal("compress() error =", err)
+			}
+			if _, err := w.Write(data); err != nil {
+				t.Fatal("Write() error =", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Close() error =", err)
+			}
+
+			rc, got, err := Decompress(&buf)
+			if err != nil {
+				t.Fatal("Decompress() error =", err)
+			}
+			defer rc.Close()
+			if got != c {
+				t.Errorf("Decompress() compression = %v, want %v", got, c)
+			}
+			content, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal("io.ReadAll() error =", err)
+			}
+			if !bytes.Equal(content, data) {
+				t.Error("Decompress() content mismatch")
+			}
+		})
+	}
+}
+
+func TestCompression_ValidateLevel(t *testing.T) {
+	tests := []struct {
+		compression Compression
+		level       int
+		wantErr     bool
+	}{
+		{CompressionGzip, 0, false},
+		{CompressionGzip, 9, false},
+		{CompressionGzip, 10, true},
+		{CompressionZstd, 19, false},
+		{CompressionZstd, 23, true},
+		{CompressionZstd, -1, true},
+		{CompressionNone, 0, false},
+		{CompressionNone, 1, true},
+	}
+	for _, tt := range tests {
+		if err := tt.compression.ValidateLevel(tt.level); (err != nil) != tt.wantErr {
+			t.Errorf("Compression(%q).ValidateLevel(%d) error = %v, wantErr %v", tt.compression, tt.level, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	if got, err := ParseCompression(""); err != nil || got != CompressionGzip {
+		t.Errorf("ParseCompression(\"\") = %v, %v, want %v", got, err, CompressionGzip)
+	}
+	if got, err := ParseCompression("zstd"); err != nil || got != CompressionZstd {
+		t.Errorf("ParseCompression(\"zstd\") = %v, %v, want %v", got, err, CompressionZstd)
+	}
+	if _, err := ParseCompression("bzip2"); err == nil {
+		t.Error("ParseCompression(\"bzip2\") error = nil, want error")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractTar extracts the tarball read from r to the directory dir. The names
+// of the entries must be under prefix, which is trimmed on extraction.
+func ExtractTar(dir, prefix string, r io.Reader) error {
+	tr := tar.NewReader(r)
+	buf := make([]byte, 32*1024)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		// name check
+		path, err := ensureBasePath(dir, prefix, header.Name)
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(dir, path)
+
+		// create content
+		switch header.Typeflag {
+		case tar.TypeReg:
+			err = writeFile(path, tr, header.FileInfo().Mode(), buf)
+		case tar.TypeDir:
+			err = os.MkdirAll(path, header.FileInfo().Mode())
+		case tar.TypeLink:
+			var target string
+			if target, err = ensureLinkPath(dir, prefix, path, header.Linkname); err == nil {
+				err = os.Link(target, path)
+			}
+		case tar.TypeSymlink:
+			var target string
+			if target, err = ensureLinkPath(dir, prefix, path, header.Linkname); err == nil {
+				err = os.Symlink(target, path)
+			}
+		default:
+			continue // non-regular files are skipped
+		}
+		if err != nil {
+			return err
+		}
+
+		// change access time and modification time if possible (error ignored)
+		_ = os.Chtimes(path, header.AccessTime, header.ModTime)
+	}
+}
+
+// ensureBasePath ensures the target path is in the base path, returning its
+// relative path to the base path. target can be either an absolute path or a
+// relative path.
+func ensureBasePath(baseAbs, baseRel, target string) (string, error) {
+	base := baseRel
+	if filepath.IsAbs(target) {
+		// ensure base and target are consistent
+		base = baseAbs
+	}
+	path, err := filepath.Rel(base, target)
+	if err != nil {
+		return "", err
+	}
+	cleanPath := filepath.ToSlash(filepath.Clean(path))
+	if cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return "", fmt.Errorf("%q is outside of %q", target, baseRel)
+	}
+
+	// no symbolic link allowed in the relative path
+	dir := filepath.Dir(path)
+	for dir != "." {
+		if info, err := os.Lstat(filepath.Join(baseAbs, dir)); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		} else if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("no symbolic link allowed between %q and %q", baseRel, target)
+		}
+		dir = filepath.Dir(dir)
+	}
+	return path, nil
+}
+
+// ensureLinkPath ensures the target path pointed by the link is in the base
+// path. It returns target path if validated.
+func ensureLinkPath(baseAbs, baseRel, link, target string) (string, error) {
+	path := target
+	if !filepath.IsAbs(target) {
+		path = filepath.Join(filepath.Dir(link), target)
+	}
+	if _, err := ensureBasePath(baseAbs, baseRel, path); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// writeFile writes the content read from r to path.
+func writeFile(path string, r io.Reader, perm os.FileMode, buf []byte) (err error) {
+	fp, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := fp.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	_, err = io.CopyBuffer(fp, r, buf)
+	return err
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+	ofile "oras.land/oras/internal/file"
+)
+
+// ListTar lists the entries of the tarball read from r, with the digests of
+// the regular files computed. The names of the entries must be under prefix.
+// Hard links are listed as the regular files they link to, and other
+// non-regular files are skipped, as ExtractTar does.
+func ListTar(prefix string, r io.Reader) ([]ofile.Entry, error) {
+	prefix = path.Clean(prefix)
+	tr := tar.NewReader(r)
+	var entries []ofile.Entry
+	files := make(map[string]ofile.Entry)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return entries, nil
+			}
+			return nil, err
+		}
+
+		// name check
+		name := path.Clean(header.Name)
+		if name != prefix && !strings.HasPrefix(name, prefix+"/") {
+			return nil, fmt.Errorf("%q is outside of %q", header.Name, prefix)
+		}
+
+		entry := ofile.Entry{Name: name}
+		switch header.Typeflag {
+		case tar.TypeReg:
+			digester := digest.Canonical.Digester()
+			if entry.Size, err = io.Copy(digester.Hash(), tr); err != nil {
+				return nil, err
+			}
+			entry.Digest = digester.Digest()
+			files[name] = entry
+		case tar.TypeDir:
+			entry.Type = fs.ModeDir
+		case tar.TypeLink:
+			target, ok := files[path.Clean(header.Linkname)]
+			if !ok {
+				return nil, fmt.Errorf("hard link %q points to %q not found in the tarball", header.Name, header.Linkname)
+			}
+			entry.Digest, entry.Size = target.Digest, target.Size
+		case tar.TypeSymlink:
+			entry.Type = fs.ModeSymlink
+			entry.Linkname = header.Linkname
+		default:
+			continue // non-regular files are skipped
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ofile "oras.land/oras/internal/file"
+)
+
+// buildTar builds a tarball with the headers, where the regular files have
+// their names as the content.
+func buildTar(t *testing.T, headers ...*tar.Header) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, header := range headers {
+		if header.Typeflag == tar.TypeReg {
+			header.Size = int64(len(header.Name))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal("tar.Writer.WriteHeader() error =", err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(header.Name)); err != nil {
+				t.Fatal("tar.Writer.Write() error =", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("tar.Writer.Close() error =", err)
+	}
+	return buf.Bytes()
+}
+
+func TestListTar(t *testing.T) {
+	tarball := buildTar(t,
+		&tar.Header{Name: "dir/", Typeflag: tar.TypeDir},
+		&tar.Header{Name: "dir/a", Typeflag: tar.TypeReg},
+		&tar.Header{Name: "dir/b", Typeflag: tar.TypeLink, Linkname: "dir/a"},
+		&tar.Header{Name: "dir/l", Typeflag: tar.TypeSymlink, Linkname: "a"},
+		&tar.Header{Name: "dir/fifo", Typeflag: tar.TypeFifo},
+	)
+	got, err := ListTar("dir", bytes.NewReader(tarball))
+	if err != nil {
+		t.Fatal("ListTar() error =", err)
+	}
+	dgst := digest.FromString("dir/a")
+	want := []ofile.Entry{
+		{Name: "dir", Type: fs.ModeDir},
+		{Name: "dir/a", Digest: dgst, Size: 5},
+		{Name: "dir/b", Digest: dgst, Size: 5},
+		{Name: "dir/l", Type: fs.ModeSymlink, Linkname: "a"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListTar() = %v, want %v", got, want)
+	}
+}
+
+func TestListTar_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		tarball []byte
+	}{
+		{
+			name:    "outside of prefix",
+			tarball: buildTar(t, &tar.Header{Name: "other/a", Typeflag: tar.TypeReg}),
+		},
+		{
+			name:    "prefix as a prefix of the name",
+			tarball: buildTar(t, &tar.Header{Name: "dir2/a", Typeflag: tar.TypeReg}),
+		},
+		{
+			name:    "hard link to missing file",
+			tarball: buildTar(t, &tar.Header{Name: "dir/b", Typeflag: tar.TypeLink, Linkname: "dir/a"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ListTar("dir", bytes.NewReader(tt.tarball)); err == nil {
+				t.Error("ListTar() error = nil, want error")
+			}
+		})
+	}
+}
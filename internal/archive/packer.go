@@ -0,0 +1,67 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"errors"
+	"os"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Packer packs directories into compressed tarballs stored in a temporary
+// directory.
+type Packer struct {
+	Options
+	dir string
+}
+
+// NewPacker creates a packer with the given options.
+func NewPacker(opts Options) *Packer {
+	return &Packer{Options: opts}
+}
+
+// Pack packs the directory at root with entries placed under name, and returns
+// the path of the packed tarball and the digest of the uncompressed tarball.
+// The packed tarball is removed when the packer is closed.
+func (p *Packer) Pack(root, name string) (path string, tarDigest digest.Digest, err error) {
+	if p.dir == "" {
+		if p.dir, err = os.MkdirTemp("", "oras_pack_*"); err != nil {
+			return "", "", err
+		}
+	}
+	fp, err := os.CreateTemp(p.dir, "*.tar")
+	if err != nil {
+		return "", "", err
+	}
+	path = fp.Name()
+	tarDigest, err = PackDirectory(fp, root, name, p.Options)
+	if closeErr := fp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", errors.Join(err, os.Remove(path))
+	}
+	return path, tarDigest, nil
+}
+
+// Close removes all the tarballs packed by the packer.
+func (p *Packer) Close() error {
+	if p == nil || p.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(p.dir)
+}
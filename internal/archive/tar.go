@@ -0,0 +1,170 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archive packs directories into layers.
+package archive
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Options configures how directories are packed.
+type Options struct {
+	// Reproducible normalizes the metadata of the entries so that identical
+	// trees always yield identical tarballs. The ownership is removed, the
+	// permissions are normalized and the modification times are set to
+	// ModTime.
+	Reproducible bool
+	// ModTime is the modification time of all entries in reproducible mode,
+	// e.g. SOURCE_DATE_EPOCH. The Unix epoch is used if not specified.
+	ModTime time.Time
+	// Compression is the compression algorithm of the packed tarballs.
+	// Defaults to gzip.
+	Compression Compression
+	// CompressionLevel is the compression level, where 0 stands for the
+	// default level of the compression algorithm.
+	CompressionLevel int
+}
+
+// PackDirectory writes the directory at root to w as a compressed tarball,
+// with the entries placed under name, and returns the digest of the
+// uncompressed tarball.
+func PackDirectory(w io.Writer, root, name string, opts Options) (dgst digest.Digest, err error) {
+	cw, err := compress(w, opts.Compression, opts.CompressionLevel, opts.Reproducible)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		closeErr := cw.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	tarDigester := digest.Canonical.Digester()
+	if err := TarDirectory(io.MultiWriter(cw, tarDigester.Hash()), root, name, opts); err != nil {
+		return "", fmt.Errorf("failed to tar %s: %w", root, err)
+	}
+	return tarDigester.Digest(), nil
+}
+
+// TarDirectory writes the directory at root to w as a tarball, with the
+// entries placed under prefix in lexical order.
+func TarDirectory(w io.Writer, root, prefix string, opts Options) (err error) {
+	tw := tar.NewWriter(w)
+	defer func() {
+		closeErr := tw.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	modTime := opts.ModTime
+	if modTime.IsZero() {
+		modTime = time.Unix(0, 0)
+	}
+	buf := make([]byte, 32*1024)
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) (returnErr error) {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		// rename path
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(filepath.Join(prefix, name))
+
+		// generate header
+		var link string
+		mode := info.Mode()
+		if mode&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		header.Name = name
+		header.Uid = 0
+		header.Gid = 0
+		header.Uname = ""
+		header.Gname = ""
+		if opts.Reproducible {
+			normalizeHeader(header, modTime)
+		}
+
+		// write file
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("tar: %w", err)
+		}
+		if mode.IsRegular() {
+			fp, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				closeErr := fp.Close()
+				if returnErr == nil {
+					returnErr = closeErr
+				}
+			}()
+			if _, err := io.CopyBuffer(tw, fp, buf); err != nil {
+				return fmt.Errorf("failed to copy to %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// normalizeHeader removes the metadata of header depending on the build
+// environment.
+func normalizeHeader(header *tar.Header, modTime time.Time) {
+	switch header.Typeflag {
+	case tar.TypeDir:
+		header.Mode = 0755
+	case tar.TypeSymlink:
+		header.Mode = 0777
+	default:
+		if header.Mode&0111 != 0 {
+			header.Mode = 0755
+		} else {
+			header.Mode = 0644
+		}
+	}
+	header.ModTime = modTime.UTC().Truncate(time.Second)
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	header.PAXRecords = nil
+	header.Xattrs = nil //nolint:staticcheck // cleared for reproducibility
+	header.Devmajor = 0
+	header.Devminor = 0
+	header.Format = tar.FormatPAX
+}
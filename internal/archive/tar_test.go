@@ -0,0 +1,162 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTree creates a small directory tree under root with the given
+// modification time and permissions.
+func writeTree(t *testing.T, root string, modTime time.Time, perm os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0700); err != nil {
+		t.Fatal("os.MkdirAll() error =", err)
+	}
+	files := map[string]string{
+		"b.txt":     "bar",
+		"a.txt":     "foo",
+		"sub/c.txt": "baz",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(content), perm); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+		if err := os.Chmod(path, perm); err != nil {
+			t.Fatal("os.Chmod() error =", err)
+		}
+	}
+	for _, name := range []string{"a.txt", "b.txt", "sub/c.txt", "sub", "."} {
+		if err := os.Chtimes(filepath.Join(root, name), modTime, modTime); err != nil {
+			t.Fatal("os.Chtimes() error =", err)
+		}
+	}
+}
+
+func TestPackDirectory_reproducible(t *testing.T) {
+	dir1 := t.TempDir()
+	writeTree(t, dir1, time.Now(), 0600)
+	dir2 := t.TempDir()
+	writeTree(t, dir2, time.Now().Add(-time.Hour), 0640)
+
+	opts := Options{Reproducible: true}
+	var buf1, buf2 bytes.Buffer
+	dgst1, err := PackDirectory(&buf1, dir1, "data", opts)
+	if err != nil {
+		t.Fatal("PackDirectory() error =", err)
+	}
+	dgst2, err := PackDirectory(&buf2, dir2, "data", opts)
+	if err != nil {
+		t.Fatal("PackDirectory() error =", err)
+	}
+	if dgst1 != dgst2 {
+		t.Errorf("PackDirectory() tar digests differ: %v, %v", dgst1, dgst2)
+	}
+	if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Error("PackDirectory() gzipped tarballs differ")
+	}
+
+	// the default mode keeps the metadata
+	buf1.Reset()
+	buf2.Reset()
+	if _, err := PackDirectory(&buf1, dir1, "data", Options{}); err != nil {
+		t.Fatal("PackDirectory() error =", err)
+	}
+	if _, err := PackDirectory(&buf2, dir2, "data", Options{}); err != nil {
+		t.Fatal("PackDirectory() error =", err)
+	}
+	if bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Error("PackDirectory() gzipped tarballs are identical without reproducible mode")
+	}
+}
+
+func TestPackDirectory_normalizesHeaders(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, time.Now(), 0700)
+	modTime := time.Unix(1700000000, 0)
+
+	var buf bytes.Buffer
+	if _, err := PackDirectory(&buf, dir, "data", Options{Reproducible: true, ModTime: modTime}); err != nil {
+		t.Fatal("PackDirectory() error =", err)
+	}
+	gzr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal("gzip.NewReader() error =", err)
+	}
+	if !gzr.ModTime.IsZero() || gzr.Name != "" {
+		t.Errorf("gzip header = %+v, want empty", gzr.Header)
+	}
+
+	want := []struct {
+		name string
+		mode int64
+	}{
+		{"data", 0755},
+		{"data/a.txt", 0755},
+		{"data/b.txt", 0755},
+		{"data/sub", 0755},
+		{"data/sub/c.txt", 0755},
+	}
+	tr := tar.NewReader(gzr)
+	for _, w := range want {
+		header, err := tr.Next()
+		if err != nil {
+			t.Fatal("tar.Reader.Next() error =", err)
+		}
+		if header.Name != w.name && header.Name != w.name+"/" {
+			t.Errorf("entry name = %v, want %v", header.Name, w.name)
+		}
+		if header.Mode != w.mode {
+			t.Errorf("%s: mode = %o, want %o", header.Name, header.Mode, w.mode)
+		}
+		if !header.ModTime.Equal(modTime) {
+			t.Errorf("%s: mod time = %v, want %v", header.Name, header.ModTime, modTime)
+		}
+		if header.Uid != 0 || header.Gid != 0 || header.Uname != "" || header.Gname != "" {
+			t.Errorf("%s: ownership = %d:%d (%s:%s), want empty", header.Name, header.Uid, header.Gid, header.Uname, header.Gname)
+		}
+	}
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("tar.Reader.Next() error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestPacker_Close(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, time.Now(), 0644)
+	packer := NewPacker(Options{Reproducible: true})
+	path, _, err := packer.Pack(dir, "data")
+	if err != nil {
+		t.Fatal("Packer.Pack() error =", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("os.Stat() error =", err)
+	}
+	if err := packer.Close(); err != nil {
+		t.Fatal("Packer.Close() error =", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("os.Stat() error = %v, want not exist", err)
+	}
+}
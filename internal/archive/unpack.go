@@ -0,0 +1,182 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+)
+
+// Unpacker unpacks directories and assembles chunked files pushed to a file
+// store. Gzipped directories are unpacked by the file store itself, while
+// directories compressed by other algorithms or not compressed at all are
+// unpacked by the unpacker.
+type Unpacker struct {
+	oras.GraphTarget
+
+	// WorkingDir is the working directory of the file store.
+	WorkingDir string
+	// AllowPathTraversalOnWrite allows unpacking directories outside the
+	// working directory.
+	AllowPathTraversalOnWrite bool
+	// DisableOverwrite disables overwriting existing directories.
+	DisableOverwrite bool
+
+	unpacked sync.Map // map[digest.Digest]bool
+
+	chunkLock   sync.Mutex
+	chunkFiles  map[string]*chunkedFile
+	chunkCopies map[digest.Digest][]chunkCopy
+}
+
+// NewUnpacker creates an unpacker wrapping the file store.
+func NewUnpacker(store *file.Store, workingDir string) *Unpacker {
+	return &Unpacker{
+		GraphTarget:               store,
+		WorkingDir:                workingDir,
+		AllowPathTraversalOnWrite: store.AllowPathTraversalOnWrite,
+		DisableOverwrite:          store.DisableOverwrite,
+	}
+}
+
+// Push unpacks the content if it is a directory not compressed by gzip, or
+// writes the content into the assembled file if it is a registered chunk,
+// otherwise pushes it to the file store.
+func (u *Unpacker) Push(ctx context.Context, expected ocispec.Descriptor, r io.Reader) error {
+	if IsChunk(expected) {
+		if err := u.assemble(expected, r); err != nil {
+			return err
+		}
+		u.unpacked.Store(expected.Digest, true)
+		return nil
+	}
+	name := expected.Annotations[ocispec.AnnotationTitle]
+	if name == "" || expected.Annotations[file.AnnotationUnpack] != "true" {
+		return u.GraphTarget.Push(ctx, expected, r)
+	}
+	br := bufio.NewReader(r)
+	c, err := detectCompression(br)
+	if err != nil {
+		return err
+	}
+	if c == CompressionGzip {
+		return u.GraphTarget.Push(ctx, expected, br)
+	}
+	if err := u.unpack(name, expected, br); err != nil {
+		return err
+	}
+	u.unpacked.Store(expected.Digest, true)
+	return nil
+}
+
+// Exists returns true if the described content is unpacked or exists in the
+// file store.
+func (u *Unpacker) Exists(ctx context.Context, target ocispec.Descriptor) (bool, error) {
+	if _, ok := u.unpacked.Load(target.Digest); ok {
+		return true, nil
+	}
+	return u.GraphTarget.Exists(ctx, target)
+}
+
+// unpack verifies and extracts the directory named name.
+func (u *Unpacker) unpack(name string, expected ocispec.Descriptor, r io.Reader) (err error) {
+	target, err := u.resolveWritePath(name)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path for writing: %w", err)
+	}
+	if err := os.MkdirAll(target, 0777); err != nil {
+		return fmt.Errorf("failed to ensure directories of the target path: %w", err)
+	}
+
+	vr := content.NewVerifyReader(r, expected)
+	dr, _, err := Decompress(vr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := dr.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	var tr io.Reader = dr
+	var verifier digest.Verifier
+	if checksum, err := digest.Parse(expected.Annotations[file.AnnotationDigest]); err == nil {
+		verifier = checksum.Verifier()
+		tr = io.TeeReader(dr, verifier)
+	}
+	if err := ExtractTar(target, name, tr); err != nil {
+		return fmt.Errorf("failed to extract tar to %s: %w", target, err)
+	}
+	// consume the padding after the end of the tar archive
+	if _, err := io.Copy(io.Discard, tr); err != nil {
+		return err
+	}
+	if err := vr.Verify(); err != nil {
+		return err
+	}
+	if verifier != nil && !verifier.Verified() {
+		return fmt.Errorf("failed to extract tar to %s: content digest mismatch", target)
+	}
+	return nil
+}
+
+// resolveWritePath resolves the path to write for the given name.
+func (u *Unpacker) resolveWritePath(name string) (string, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(u.WorkingDir, path)
+	}
+	if !u.AllowPathTraversalOnWrite {
+		base, err := filepath.Abs(u.WorkingDir)
+		if err != nil {
+			return "", err
+		}
+		target, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(base, target)
+		if err != nil {
+			return "", file.ErrPathTraversalDisallowed
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, "../") || rel == ".." {
+			return "", file.ErrPathTraversalDisallowed
+		}
+	}
+	if u.DisableOverwrite {
+		if _, err := os.Stat(path); err == nil {
+			return "", file.ErrOverwriteDisallowed
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return path, nil
+}
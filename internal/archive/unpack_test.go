@@ -0,0 +1,116 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+)
+
+// packTestDirectory packs a test directory named "data" with the given
+// compression and returns its descriptor and content.
+func packTestDirectory(t *testing.T, c Compression) (ocispec.Descriptor, []byte) {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, time.Now(), 0644)
+	var buf bytes.Buffer
+	tarDigest, err := PackDirectory(&buf, dir, "data", Options{Compression: c})
+	if err != nil {
+		t.Fatal("PackDirectory() error =", err)
+	}
+	desc := content.NewDescriptorFromBytes(c.MediaType(), buf.Bytes())
+	desc.Annotations = map[string]string{
+		ocispec.AnnotationTitle: "data",
+		file.AnnotationDigest:   tarDigest.String(),
+		file.AnnotationUnpack:   "true",
+	}
+	return desc, buf.Bytes()
+}
+
+func TestUnpacker_Push(t *testing.T) {
+	for _, c := range []Compression{CompressionGzip, CompressionZstd, CompressionNone} {
+		t.Run(string(c), func(t *testing.T) {
+			desc, blob := packTestDirectory(t, c)
+			dir := t.TempDir()
+			store, err := file.New(dir)
+			if err != nil {
+				t.Fatal("file.New() error =", err)
+			}
+			defer store.Close()
+			u := NewUnpacker(store, dir)
+
+			ctx := context.Background()
+			if err := u.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+				t.Fatal("Unpacker.Push() error =", err)
+			}
+			exists, err := u.Exists(ctx, desc)
+			if err != nil {
+				t.Fatal("Unpacker.Exists() error =", err)
+			}
+			if !exists {
+				t.Error("Unpacker.Exists() = false, want true")
+			}
+			got, err := os.ReadFile(filepath.Join(dir, "data", "sub", "c.txt"))
+			if err != nil {
+				t.Fatal("os.ReadFile() error =", err)
+			}
+			if want := "baz"; string(got) != want {
+				t.Errorf("unpacked content = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestUnpacker_Push_mismatchedChecksum(t *testing.T) {
+	desc, blob := packTestDirectory(t, CompressionZstd)
+	desc.Annotations[file.AnnotationDigest] = digest.FromString("foo").String()
+	dir := t.TempDir()
+	store, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+
+	if err := NewUnpacker(store, dir).Push(context.Background(), desc, bytes.NewReader(blob)); err == nil {
+		t.Error("Unpacker.Push() error = nil, want error")
+	}
+}
+
+func TestUnpacker_Push_pathTraversal(t *testing.T) {
+	desc, blob := packTestDirectory(t, CompressionNone)
+	desc.Annotations[ocispec.AnnotationTitle] = "../data"
+	dir := t.TempDir()
+	store, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+
+	err = NewUnpacker(store, dir).Push(context.Background(), desc, bytes.NewReader(blob))
+	if !errors.Is(err, file.ErrPathTraversalDisallowed) {
+		t.Errorf("Unpacker.Push() error = %v, want %v", err, file.ErrPathTraversalDisallowed)
+	}
+}
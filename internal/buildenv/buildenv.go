@@ -0,0 +1,138 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package buildenv reads the standard OCI annotations describing the source
+// of an artifact from the build environment.
+package buildenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// scpLikeURL matches git remote URLs in the scp-like syntax, e.g.
+// git@github.com:oras-project/oras.git.
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):([^/].*)$`)
+
+// FromGit returns the annotations for the revision, the source and the version
+// of the HEAD commit of the git repository containing dir. The source is read
+// from the remote "origin" and the version from a tag pointing at HEAD, both
+// are omitted if not available.
+func FromGit(ctx context.Context, dir string) (map[string]string, error) {
+	revision, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the HEAD commit of the git repository: %w", err)
+	}
+	annotations := map[string]string{
+		ocispec.AnnotationRevision: revision,
+	}
+	if remote, err := git(ctx, dir, "remote", "get-url", "origin"); err == nil && remote != "" {
+		annotations[ocispec.AnnotationSource] = SourceURL(remote)
+	}
+	if tag, err := git(ctx, dir, "describe", "--tags", "--exact-match", "HEAD"); err == nil && tag != "" {
+		annotations[ocispec.AnnotationVersion] = tag
+	}
+	return annotations, nil
+}
+
+// git runs the git command in dir and returns the trimmed output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SourceURL converts a git remote URL to a URL suitable for the source
+// annotation. Credentials are removed and scp-like remotes are converted to
+// HTTPS URLs.
+func SourceURL(remote string) string {
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		u.User = nil
+		if u.Scheme == "ssh" || u.Scheme == "git" {
+			u.Scheme = "https"
+			u.Host = u.Hostname()
+		}
+		return u.String()
+	}
+	if m := scpLikeURL.FindStringSubmatch(remote); m != nil {
+		return "https://" + m[1] + "/" + m[2]
+	}
+	return remote
+}
+
+// FromCI returns the annotations for the revision, the source and the version
+// from the environment variables of supported CI systems, read via getenv.
+// Supported CI systems are GitHub Actions, GitLab CI/CD, Azure Pipelines and
+// CircleCI. An empty map is returned if no CI system is detected.
+func FromCI(getenv func(string) string) map[string]string {
+	var revision, source, version string
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		revision = getenv("GITHUB_SHA")
+		if server, repo := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"); server != "" && repo != "" {
+			source = strings.TrimSuffix(server, "/") + "/" + repo
+		}
+		if getenv("GITHUB_REF_TYPE") == "tag" {
+			version = getenv("GITHUB_REF_NAME")
+		}
+	case getenv("GITLAB_CI") == "true":
+		revision = getenv("CI_COMMIT_SHA")
+		source = getenv("CI_PROJECT_URL")
+		version = getenv("CI_COMMIT_TAG")
+	case strings.EqualFold(getenv("TF_BUILD"), "true"):
+		revision = getenv("BUILD_SOURCEVERSION")
+		if uri := getenv("BUILD_REPOSITORY_URI"); uri != "" {
+			source = SourceURL(uri)
+		}
+		if tag, ok := strings.CutPrefix(getenv("BUILD_SOURCEBRANCH"), "refs/tags/"); ok {
+			version = tag
+		}
+	case getenv("CIRCLECI") == "true":
+		revision = getenv("CIRCLE_SHA1")
+		if uri := getenv("CIRCLE_REPOSITORY_URL"); uri != "" {
+			source = SourceURL(uri)
+		}
+		version = getenv("CIRCLE_TAG")
+	}
+
+	annotations := make(map[string]string)
+	for key, value := range map[string]string{
+		ocispec.AnnotationRevision: revision,
+		ocispec.AnnotationSource:   source,
+		ocispec.AnnotationVersion:  version,
+	} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,82 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+)
+
+// graphTarget is a cached target supporting graph traversal. Predecessors
+// and referrers are always listed by the origin since they may change over
+// time, while the content is fetched via the cache.
+type graphTarget struct {
+	oras.ReadOnlyTarget
+	origin oras.ReadOnlyGraphTarget
+}
+
+// NewGraph generates a new graph target storage with caching, where
+// references are resolved according to opts.
+func NewGraph(source oras.ReadOnlyGraphTarget, cache content.Storage, opts ResolveOptions) oras.ReadOnlyGraphTarget {
+	return &graphTarget{
+		ReadOnlyTarget: NewWithResolveOptions(source, cache, opts),
+		origin:         source,
+	}
+}
+
+// FetchReference fetches the content identified by the reference.
+func (t *graphTarget) FetchReference(ctx context.Context, reference string) (ocispec.Descriptor, io.ReadCloser, error) {
+	if refFetcher, ok := t.ReadOnlyTarget.(registry.ReferenceFetcher); ok {
+		return refFetcher.FetchReference(ctx, reference)
+	}
+	desc, err := t.Resolve(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	rc, err := t.Fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	return desc, rc, nil
+}
+
+// Predecessors returns the nodes directly pointing to the current node.
+func (t *graphTarget) Predecessors(ctx context.Context, node ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	return t.origin.Predecessors(ctx, node)
+}
+
+// Referrers lists the descriptors of image or artifact manifests directly
+// referencing the given manifest descriptor.
+func (t *graphTarget) Referrers(ctx context.Context, desc ocispec.Descriptor, artifactType string, fn func(referrers []ocispec.Descriptor) error) error {
+	if lister, ok := t.origin.(registry.ReferrerLister); ok {
+		return lister.Referrers(ctx, desc, artifactType, fn)
+	}
+	// hide the referrers lister so that the referrers are found from the
+	// predecessors, with the manifests fetched via the cache
+	referrers, err := registry.Referrers(ctx, struct{ content.ReadOnlyGraphStorage }{t}, desc, artifactType)
+	if err != nil {
+		return err
+	}
+	if len(referrers) == 0 {
+		return nil
+	}
+	return fn(referrers)
+}
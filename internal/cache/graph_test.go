@@ -0,0 +1,90 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry"
+)
+
+func TestGraphTarget_Referrers(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	push := func(mediaType string, blob []byte) ocispec.Descriptor {
+		t.Helper()
+		desc := content.NewDescriptorFromBytes(mediaType, blob)
+		if err := base.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+			t.Fatal("memory.Push() error =", err)
+		}
+		return desc
+	}
+	config := push(ocispec.MediaTypeEmptyJSON, []byte("{}"))
+	subjectJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{},
+	})
+	if err != nil {
+		t.Fatal("json.Marshal() error =", err)
+	}
+	subject := push(ocispec.MediaTypeImageManifest, subjectJSON)
+	referrerJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/vnd.test",
+		Config:       config,
+		Layers:       []ocispec.Descriptor{},
+		Subject:      &subject,
+	})
+	if err != nil {
+		t.Fatal("json.Marshal() error =", err)
+	}
+	referrer := push(ocispec.MediaTypeImageManifest, referrerJSON)
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	g := NewGraph(base, store, ResolveOptions{})
+	got, err := registry.Referrers(ctx, g, subject, "")
+	if err != nil {
+		t.Fatal("registry.Referrers() error =", err)
+	}
+	want := referrer
+	want.ArtifactType = "application/vnd.test"
+	if !reflect.DeepEqual(got, []ocispec.Descriptor{want}) {
+		t.Fatalf("registry.Referrers() = %v, want %v", got, []ocispec.Descriptor{want})
+	}
+
+	// the referrer manifest is fetched via the cache
+	exists, err := store.Exists(ctx, referrer)
+	if err != nil {
+		t.Fatal("Store.Exists() error =", err)
+	}
+	if !exists {
+		t.Errorf("referrer %s is not cached", referrer.Digest)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockRetryInterval is the interval between attempts to acquire a file lock
+// held by another process.
+const lockRetryInterval = 50 * time.Millisecond
+
+// lockFile acquires an exclusive advisory lock on the file at path, creating
+// the file if it does not exist. lockFile blocks until the lock is acquired or
+// ctx is done.
+func lockFile(ctx context.Context, path string) (unlock func() error, err error) {
+	return acquireLock(ctx, path, false)
+}
+
+// lockTempFile acquires an exclusive advisory lock on the file at path like
+// lockFile, and removes the file on unlock so that lock files do not pile up.
+func lockTempFile(ctx context.Context, path string) (unlock func() error, err error) {
+	return acquireLock(ctx, path, true)
+}
+
+// acquireLock locks the file at path, and removes the file on unlock if
+// remove is true.
+func acquireLock(ctx context.Context, path string, remove bool) (unlock func() error, err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if locked && remove {
+			// the file may have been removed by the previous holder after it
+			// was opened, in which case the lock is not exclusive anymore
+			same, err := isSameFile(f, path)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			if !same {
+				f.Close()
+				if f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		if locked {
+			return func() error {
+				if remove {
+					// removal is best effort, e.g. open files cannot be
+					// removed on some platforms
+					_ = os.Remove(path)
+				}
+				return errors.Join(unlockFile(f), f.Close())
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// isSameFile returns true if f is still the file at path.
+func isSameFile(f *os.File, path string) (bool, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return os.SameFile(fi, pathInfo), nil
+}
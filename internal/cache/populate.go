@@ -0,0 +1,78 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+)
+
+// PopulateOptions contains parameters for Populate.
+type PopulateOptions struct {
+	oras.CopyGraphOptions
+	// Namespace is the registry and repository the content is pushed to.
+	// Tags are not recorded if Namespace is empty.
+	Namespace string
+	// Tags are the tags of the root node in Namespace.
+	Tags []string
+}
+
+// Populate copies the graph rooted at root from src into the cache and
+// records the tags of root, as if the content had been fetched from the
+// origin via the cache.
+func Populate(ctx context.Context, cache content.Storage, src content.ReadOnlyStorage, root ocispec.Descriptor, opts PopulateOptions) error {
+	if err := oras.CopyGraph(ctx, src, cache, root, opts.CopyGraphOptions); err != nil {
+		return err
+	}
+	return RecordTags(ctx, cache, root, opts.Namespace, opts.Tags)
+}
+
+// RecordTags records tags of root in namespace in the cache, as if root had
+// been resolved from the origin via the cache. Tags are not recorded if
+// namespace is empty or root is not in the cache.
+func RecordTags(ctx context.Context, cache content.Storage, root ocispec.Descriptor, namespace string, tags []string) error {
+	if namespace == "" || len(tags) == 0 {
+		return nil
+	}
+	exists, err := cache.Exists(ctx, root)
+	if err != nil || !exists {
+		return err
+	}
+	t := &target{
+		cache: cache,
+		opts: ResolveOptions{
+			Namespace: namespace,
+		},
+	}
+	// record the descriptor as resolved from the origin
+	desc := ocispec.Descriptor{
+		MediaType: root.MediaType,
+		Digest:    root.Digest,
+		Size:      root.Size,
+	}
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if err := t.recordTag(ctx, tag, desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestPopulate(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	layer := []byte("hello world")
+	layerDesc := content.NewDescriptorFromBytes("test", layer)
+	if err := src.Push(ctx, layerDesc, bytes.NewReader(layer)); err != nil {
+		t.Fatal("memory.Push() error =", err)
+	}
+	root, err := oras.PackManifest(ctx, src, oras.PackManifestVersion1_1, "application/vnd.test", oras.PackManifestOptions{
+		Layers: []ocispec.Descriptor{layerDesc},
+	})
+	if err != nil {
+		t.Fatal("oras.PackManifest() error =", err)
+	}
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	namespace := "localhost:5000/test"
+
+	if err := Populate(ctx, store, src, root, PopulateOptions{
+		Namespace: namespace,
+		Tags:      []string{"v1", root.Digest.String(), ""},
+	}); err != nil {
+		t.Fatal("Populate() error =", err)
+	}
+
+	// the populated content can be fetched offline
+	p := NewWithResolveOptions(memory.New(), store, ResolveOptions{
+		Namespace: namespace,
+		Offline:   true,
+	})
+	desc, err := p.Resolve(ctx, "v1")
+	if err != nil {
+		t.Fatal("Proxy.Resolve() error =", err)
+	}
+	if desc.Digest != root.Digest {
+		t.Fatalf("Proxy.Resolve() = %v, want %v", desc.Digest, root.Digest)
+	}
+	for _, want := range []ocispec.Descriptor{root, layerDesc} {
+		if _, err := content.FetchAll(ctx, p, want); err != nil {
+			t.Errorf("Proxy.Fetch(%s) error = %v", want.Digest, err)
+		}
+	}
+}
+
+func TestRecordTags(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	root, err := oras.PackManifest(ctx, src, oras.PackManifestVersion1_1, "application/vnd.test", oras.PackManifestOptions{})
+	if err != nil {
+		t.Fatal("oras.PackManifest() error =", err)
+	}
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	namespace := "localhost:5000/test"
+	p := NewWithResolveOptions(memory.New(), store, ResolveOptions{
+		Namespace: namespace,
+		Offline:   true,
+	})
+
+	// tags of content not cached are not recorded
+	if err := RecordTags(ctx, store, root, namespace, []string{"v1"}); err != nil {
+		t.Fatal("RecordTags() error =", err)
+	}
+	if _, err := p.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Proxy.Resolve() error = nil, want error")
+	}
+
+	// tags of content cached are recorded
+	cached := New(src, store)
+	if _, err := content.FetchAll(ctx, cached, root); err != nil {
+		t.Fatal("Proxy.Fetch() error =", err)
+	}
+	if err := RecordTags(ctx, store, root, namespace, []string{"v1"}); err != nil {
+		t.Fatal("RecordTags() error =", err)
+	}
+	desc, err := p.Resolve(ctx, "v1")
+	if err != nil {
+		t.Fatal("Proxy.Resolve() error =", err)
+	}
+	if desc.Digest != root.Digest {
+		t.Errorf("Proxy.Resolve() = %v, want %v", desc.Digest, root.Digest)
+	}
+}
@@ -0,0 +1,505 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras/internal/descriptor"
+)
+
+const (
+	// ingestDir is the directory of temporary ingest files in an OCI store.
+	ingestDir = "ingest"
+	// lockDir is the directory of lock files coordinating processes sharing
+	// the store.
+	lockDir = "locks"
+	// indexLockFile is the name of the lock file protecting index.json.
+	indexLockFile = "index.lock"
+)
+
+// Blob records the metadata of a cached blob.
+type Blob struct {
+	Digest       digest.Digest
+	Size         int64
+	LastAccessed time.Time
+}
+
+// PruneOptions contains parameters for Store.Prune.
+type PruneOptions struct {
+	// MaxSize evicts least recently used blobs until the total size of the
+	// store does not exceed MaxSize. Not applied if not positive.
+	MaxSize int64
+	// OlderThan evicts blobs which have not been accessed within the
+	// duration. Not applied if not positive.
+	OlderThan time.Duration
+}
+
+// Store is a cache storage based on an OCI image layout. The last access time
+// of a blob is recorded as the modification time of the blob file, so that
+// the least recently used blobs can be evicted.
+//
+// A Store can be shared by multiple processes. Blobs are ingested into
+// temporary files and renamed into place, and updates to index.json are
+// serialized by an advisory file lock and applied on top of the latest
+// index.json on disk.
+type Store struct {
+	// MaxSize is the size limit of the store in bytes. If positive, least
+	// recently used blobs are evicted once new content cached pushes the
+	// store size over MaxSize.
+	MaxSize int64
+
+	sizeLock sync.Mutex // protects size and sizeKnown
+	// size is the running total size of the store, counted once by walking
+	// the store and updated by pushes and prunes of this store.
+	size      int64
+	sizeKnown bool
+
+	root      string
+	lock      sync.RWMutex // protects store and indexStat
+	store     *oci.Store
+	indexStat *fileStat // index.json as last loaded or saved by store
+}
+
+// fileStat records the size and modification time of a file to detect
+// changes.
+type fileStat struct {
+	size    int64
+	modTime time.Time
+}
+
+// statFile returns the fileStat of the file at path.
+func statFile(path string) (*fileStat, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileStat{size: fi.Size(), modTime: fi.ModTime()}, nil
+}
+
+// NewStore creates a new cache store under root.
+func NewStore(root string) (*Store, error) {
+	rootAbs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", root, err)
+	}
+	s := &Store{
+		root: rootAbs,
+	}
+	// index.json may be created on load
+	if err := s.updateIndex(context.Background(), nil); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Root returns the root directory of the store.
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Fetch fetches the content identified by the descriptor and records the
+// access time.
+func (s *Store) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := s.current().Fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	s.touch(target.Digest)
+	return rc, nil
+}
+
+// Exists returns true if the described content exists.
+func (s *Store) Exists(ctx context.Context, target ocispec.Descriptor) (bool, error) {
+	return s.current().Exists(ctx, target)
+}
+
+// Resolve resolves a reference to a descriptor. Tags recorded by other
+// processes are visible after the next update of index.json by this store.
+func (s *Store) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	return s.current().Resolve(ctx, reference)
+}
+
+// Push pushes the content into the store and evicts least recently used
+// blobs if the store size exceeds MaxSize. Content already pushed by another
+// process is not treated as an error.
+func (s *Store) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	added := expected.Size
+	if err := s.current().Push(ctx, expected, content); err != nil {
+		if !errors.Is(err, errdef.ErrAlreadyExists) {
+			return err
+		}
+		// drain the content so that the sender is not blocked
+		if _, err := io.Copy(io.Discard, content); err != nil {
+			return err
+		}
+		added = 0
+	}
+	if descriptor.IsManifest(expected) {
+		// manifests are tagged by digest in index.json
+		if err := s.updateIndex(ctx, func(store *oci.Store) error {
+			return store.Tag(ctx, expected, expected.Digest.String())
+		}); err != nil {
+			return err
+		}
+	}
+	if s.MaxSize > 0 {
+		if err := s.limitSize(ctx, added); err != nil {
+			return fmt.Errorf("failed to limit cache size: %w", err)
+		}
+	}
+	return nil
+}
+
+// limitSize adds the size of newly pushed content to the running total size
+// of the store, and prunes the store if the total exceeds MaxSize. The store
+// is only walked to count the total for the first time and to prune, so that
+// pushes do not walk the whole store each time. Content pushed by other
+// processes is counted on the next prune.
+func (s *Store) limitSize(ctx context.Context, added int64) error {
+	s.sizeLock.Lock()
+	defer s.sizeLock.Unlock()
+	if s.sizeKnown {
+		s.size += added
+	} else {
+		// the newly pushed content is counted by the walk
+		size, _, err := s.Size(ctx)
+		if err != nil {
+			return err
+		}
+		s.size = size
+		s.sizeKnown = true
+	}
+	if s.size <= s.MaxSize {
+		return nil
+	}
+	_, remaining, err := s.prune(ctx, PruneOptions{MaxSize: s.MaxSize})
+	if err != nil {
+		s.sizeKnown = false
+		return err
+	}
+	s.size = remaining
+	return nil
+}
+
+// Tag tags a descriptor with a reference string.
+func (s *Store) Tag(ctx context.Context, desc ocispec.Descriptor, reference string) error {
+	return s.updateIndex(ctx, func(store *oci.Store) error {
+		return store.Tag(ctx, desc, reference)
+	})
+}
+
+// Blobs returns all blobs in the store, sorted from the least recently used
+// to the most recently used.
+func (s *Store) Blobs(ctx context.Context) ([]Blob, error) {
+	blobsDir := filepath.Join(s.root, ocispec.ImageBlobsDir)
+	algDirs, err := os.ReadDir(blobsDir)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []Blob
+	for _, algDir := range algDirs {
+		if !algDir.IsDir() {
+			continue
+		}
+		alg := digest.Algorithm(algDir.Name())
+		if !alg.Available() {
+			// skip unsupported directories
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(blobsDir, algDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			dgst := digest.NewDigestFromEncoded(alg, entry.Name())
+			if entry.IsDir() || dgst.Validate() != nil {
+				// skip irrelevant content
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					// evicted concurrently
+					continue
+				}
+				return nil, err
+			}
+			blobs = append(blobs, Blob{
+				Digest:       dgst,
+				Size:         info.Size(),
+				LastAccessed: info.ModTime(),
+			})
+		}
+	}
+	slices.SortFunc(blobs, func(a, b Blob) int {
+		return a.LastAccessed.Compare(b.LastAccessed)
+	})
+	return blobs, nil
+}
+
+// Size returns the total size and the number of blobs in the store.
+func (s *Store) Size(ctx context.Context) (size int64, count int, err error) {
+	blobs, err := s.Blobs(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, b := range blobs {
+		size += b.Size
+	}
+	return size, len(blobs), nil
+}
+
+// Prune evicts blobs from the store according to opts, starting from the
+// least recently used one. The evicted blobs are returned.
+func (s *Store) Prune(ctx context.Context, opts PruneOptions) ([]Blob, error) {
+	evicted, _, err := s.prune(ctx, opts)
+	return evicted, err
+}
+
+// prune evicts blobs like Prune, and returns the total size of the remaining
+// blobs as well.
+func (s *Store) prune(ctx context.Context, opts PruneOptions) (evicted []Blob, remaining int64, err error) {
+	blobs, err := s.Blobs(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	for _, b := range blobs {
+		total += b.Size
+	}
+	now := time.Now()
+	var victims []Blob
+	for _, b := range blobs {
+		expired := opts.OlderThan > 0 && now.Sub(b.LastAccessed) > opts.OlderThan
+		oversized := opts.MaxSize > 0 && total > opts.MaxSize
+		if !expired && !oversized {
+			// blobs are sorted by access time so the rest are all retained
+			break
+		}
+		total -= b.Size
+		victims = append(victims, b)
+	}
+	if len(victims) == 0 {
+		return nil, total, nil
+	}
+	evicted, err = s.evict(ctx, victims)
+	// count back the victims failed to evict
+	for _, b := range victims[len(evicted):] {
+		total += b.Size
+	}
+	return evicted, total, err
+}
+
+// Clean removes all blobs and temporary ingest files from the store. The
+// removed blobs are returned.
+func (s *Store) Clean(ctx context.Context) ([]Blob, error) {
+	blobs, err := s.Blobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.evict(ctx, blobs)
+	if err != nil {
+		return removed, err
+	}
+	if err := os.RemoveAll(filepath.Join(s.root, ingestDir)); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+// Delete evicts the content identified by the descriptor and any tag pointing
+// to it from the store.
+func (s *Store) Delete(ctx context.Context, target ocispec.Descriptor) error {
+	_, err := s.evict(ctx, []Blob{{Digest: target.Digest, Size: target.Size}})
+	return err
+}
+
+// Verify verifies the digests of all blobs in the store. fn is called for
+// every blob with the verification error, which is nil if the blob is intact.
+func (s *Store) Verify(ctx context.Context, fn func(b Blob, err error) error) error {
+	blobs, err := s.Blobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range blobs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		verifyErr := s.verify(b)
+		if errors.Is(verifyErr, os.ErrNotExist) {
+			// evicted concurrently
+			continue
+		}
+		if err := fn(b, verifyErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verify verifies the digest of a blob.
+func (s *Store) verify(b Blob) error {
+	f, err := os.Open(s.blobPath(b.Digest))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	verifier := b.Digest.Verifier()
+	if _, err := io.Copy(verifier, f); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("%s: %w", b.Digest, content.ErrMismatchedDigest)
+	}
+	return nil
+}
+
+// evict removes blobs and any tag pointing to them from the store. The
+// evicted blobs are returned.
+func (s *Store) evict(ctx context.Context, blobs []Blob) ([]Blob, error) {
+	var evicted []Blob
+	err := s.updateIndex(ctx, func(store *oci.Store) error {
+		for _, b := range blobs {
+			desc, err := store.Resolve(ctx, b.Digest.String())
+			if err != nil {
+				desc = ocispec.Descriptor{
+					Digest: b.Digest,
+					Size:   b.Size,
+				}
+			}
+			if err := store.Delete(ctx, desc); err != nil && !errors.Is(err, errdef.ErrNotFound) {
+				return fmt.Errorf("failed to evict %s: %w", b.Digest, err)
+			}
+			evicted = append(evicted, b)
+		}
+		return nil
+	})
+	return evicted, err
+}
+
+// updateIndex reloads index.json while holding the index lock, applies fn to
+// the reloaded store if fn is not nil and saves index.json. Changes made to
+// index.json by other processes are therefore never overwritten. index.json
+// is only reloaded if it has changed since last loaded or saved.
+func (s *Store) updateIndex(ctx context.Context, fn func(store *oci.Store) error) error {
+	unlock, err := lockFile(ctx, filepath.Join(s.root, lockDir, indexLockFile))
+	if err != nil {
+		return fmt.Errorf("failed to lock index: %w", err)
+	}
+	defer unlock()
+
+	store, err := s.loadIndex(ctx)
+	if err != nil {
+		return err
+	}
+	var saveErr error
+	if fn != nil {
+		err = fn(store)
+		// partial changes are saved as well
+		if saveErr = store.SaveIndex(); saveErr != nil {
+			err = errors.Join(err, saveErr)
+		}
+	}
+	// force a reload next time if store does not match index.json
+	var indexStat *fileStat
+	if saveErr == nil {
+		indexStat, _ = statFile(filepath.Join(s.root, ocispec.ImageIndexFile))
+	}
+	s.setCurrent(store, indexStat)
+	return err
+}
+
+// loadIndex returns the current OCI store if index.json has not changed
+// since last loaded or saved, or loads a new one from index.json otherwise.
+// loadIndex must be called while holding the index lock.
+func (s *Store) loadIndex(ctx context.Context) (*oci.Store, error) {
+	s.lock.RLock()
+	store, indexStat := s.store, s.indexStat
+	s.lock.RUnlock()
+	if store != nil && indexStat != nil {
+		stat, err := statFile(filepath.Join(s.root, ocispec.ImageIndexFile))
+		if err == nil && stat.size == indexStat.size && stat.modTime.Equal(indexStat.modTime) {
+			return store, nil
+		}
+	}
+
+	store, err := oci.NewWithContext(ctx, s.root)
+	if err != nil {
+		return nil, err
+	}
+	// evictions are accounted blob by blob
+	store.AutoGC = false
+	// index.json is only saved while holding the index lock
+	store.AutoSaveIndex = false
+	return store, nil
+}
+
+// lockIngest blocks until no other process or goroutine is ingesting the
+// content identified by dgst, and locks it for ingestion.
+func (s *Store) lockIngest(ctx context.Context, dgst digest.Digest) (unlock func() error, err error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+	return lockTempFile(ctx, filepath.Join(s.root, lockDir, dgst.Algorithm().String(), dgst.Encoded()))
+}
+
+// current returns the OCI store loaded from the latest index.json.
+func (s *Store) current() *oci.Store {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.store
+}
+
+// setCurrent replaces the OCI store with store loaded from or saved as the
+// index.json described by indexStat.
+func (s *Store) setCurrent(store *oci.Store, indexStat *fileStat) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.store = store
+	s.indexStat = indexStat
+}
+
+// touch records the access time of the blob identified by dgst.
+// Failures are ignored since the access time only affects eviction order.
+func (s *Store) touch(dgst digest.Digest) {
+	if err := dgst.Validate(); err != nil {
+		return
+	}
+	now := time.Now()
+	_ = os.Chtimes(s.blobPath(dgst), now, now)
+}
+
+// blobPath returns the file path of the blob identified by dgst.
+func (s *Store) blobPath(dgst digest.Digest) string {
+	return filepath.Join(s.root, ocispec.ImageBlobsDir, dgst.Algorithm().String(), dgst.Encoded())
+}
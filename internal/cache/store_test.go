@@ -0,0 +1,379 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+func pushBlob(t *testing.T, s *Store, blob []byte, accessed time.Time) ocispec.Descriptor {
+	t.Helper()
+	desc := content.NewDescriptorFromBytes("test", blob)
+	if err := s.Push(context.Background(), desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal("Store.Push() error =", err)
+	}
+	if err := os.Chtimes(s.blobPath(desc.Digest), accessed, accessed); err != nil {
+		t.Fatal("os.Chtimes() error =", err)
+	}
+	return desc
+}
+
+func blobDigests(blobs []Blob) []digest.Digest {
+	var digests []digest.Digest
+	for _, b := range blobs {
+		digests = append(digests, b.Digest)
+	}
+	return digests
+}
+
+func TestStore_Fetch_recordsAccess(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	desc := pushBlob(t, s, []byte("hello"), past)
+
+	rc, err := s.Fetch(ctx, desc)
+	if err != nil {
+		t.Fatal("Store.Fetch() error =", err)
+	}
+	rc.Close()
+
+	blobs, err := s.Blobs(ctx)
+	if err != nil {
+		t.Fatal("Store.Blobs() error =", err)
+	}
+	if len(blobs) != 1 {
+		t.Fatalf("Store.Blobs() got %d blobs, want 1", len(blobs))
+	}
+	if !blobs[0].LastAccessed.After(past) {
+		t.Errorf("Store.Fetch() did not record access time, got %v", blobs[0].LastAccessed)
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	oldest := pushBlob(t, s, []byte("oldest"), now.Add(-72*time.Hour))
+	older := pushBlob(t, s, []byte("older"), now.Add(-48*time.Hour))
+	newer := pushBlob(t, s, []byte("newer"), now.Add(-time.Minute))
+
+	// prune by access time
+	evicted, err := s.Prune(ctx, PruneOptions{OlderThan: 60 * time.Hour})
+	if err != nil {
+		t.Fatal("Store.Prune() error =", err)
+	}
+	if got := blobDigests(evicted); len(got) != 1 || got[0] != oldest.Digest {
+		t.Fatalf("Store.Prune() evicted %v, want %v", got, oldest.Digest)
+	}
+
+	// prune by size
+	evicted, err = s.Prune(ctx, PruneOptions{MaxSize: newer.Size})
+	if err != nil {
+		t.Fatal("Store.Prune() error =", err)
+	}
+	if got := blobDigests(evicted); len(got) != 1 || got[0] != older.Digest {
+		t.Fatalf("Store.Prune() evicted %v, want %v", got, older.Digest)
+	}
+	size, count, err := s.Size(ctx)
+	if err != nil {
+		t.Fatal("Store.Size() error =", err)
+	}
+	if size != newer.Size || count != 1 {
+		t.Errorf("Store.Size() = (%d, %d), want (%d, %d)", size, count, newer.Size, 1)
+	}
+}
+
+func TestStore_Push_maxSize(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	s.MaxSize = 10
+	ctx := context.Background()
+	first := pushBlob(t, s, []byte("first"), time.Now().Add(-time.Hour))
+	second := pushBlob(t, s, []byte("second"), time.Now())
+
+	exists, err := s.Exists(ctx, first)
+	if err != nil {
+		t.Fatal("Store.Exists() error =", err)
+	}
+	if exists {
+		t.Errorf("least recently used blob %s is not evicted", first.Digest)
+	}
+	exists, err = s.Exists(ctx, second)
+	if err != nil {
+		t.Fatal("Store.Exists() error =", err)
+	}
+	if !exists {
+		t.Errorf("most recently used blob %s is evicted", second.Digest)
+	}
+}
+
+func TestStore_Push_maxSize_runningTotal(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	s.MaxSize = 10
+	ctx := context.Background()
+	now := time.Now()
+	pushBlob(t, s, []byte("foo"), now.Add(-3*time.Hour))
+	pushBlob(t, s, []byte("bar"), now.Add(-2*time.Hour))
+	// pushing existing content does not add up
+	pushBlob(t, s, []byte("foo"), now.Add(-3*time.Hour))
+	pushBlob(t, s, []byte("hello"), now.Add(-time.Hour))
+	pushBlob(t, s, []byte("world"), now)
+
+	size, _, err := s.Size(ctx)
+	if err != nil {
+		t.Fatal("Store.Size() error =", err)
+	}
+	if want := int64(10); size != want {
+		t.Errorf("Store.Size() = %d, want %d", size, want)
+	}
+	if s.size != size {
+		t.Errorf("running total size = %d, want %d", s.size, size)
+	}
+}
+
+func TestStore_Prune_untagsManifest(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`)
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifest)
+	if err := s.Push(ctx, desc, bytes.NewReader(manifest)); err != nil {
+		t.Fatal("Store.Push() error =", err)
+	}
+	if err := s.Tag(ctx, desc, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	if _, err := s.Prune(ctx, PruneOptions{MaxSize: 1}); err != nil {
+		t.Fatal("Store.Prune() error =", err)
+	}
+	if _, err := s.Resolve(ctx, "v1"); err == nil {
+		t.Error("Store.Resolve() error = nil, want error for evicted manifest")
+	}
+}
+
+func TestStore_Clean(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	pushBlob(t, s, []byte("foo"), time.Now())
+	pushBlob(t, s, []byte("bar"), time.Now())
+
+	removed, err := s.Clean(ctx)
+	if err != nil {
+		t.Fatal("Store.Clean() error =", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Store.Clean() removed %d blobs, want 2", len(removed))
+	}
+	if _, count, err := s.Size(ctx); err != nil || count != 0 {
+		t.Errorf("Store.Size() count = %d, error = %v, want 0 blobs", count, err)
+	}
+}
+
+func TestStore_sharedRoot(t *testing.T) {
+	root := t.TempDir()
+	s1, err := NewStore(root)
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	s2, err := NewStore(root)
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`)
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifest)
+
+	// concurrent pushes of the same content both succeed
+	var wg sync.WaitGroup
+	for _, s := range []*Store{s1, s2} {
+		wg.Add(1)
+		go func(s *Store) {
+			defer wg.Done()
+			if err := s.Push(ctx, desc, bytes.NewReader(manifest)); err != nil {
+				t.Error("Store.Push() error =", err)
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	// tags from both stores are kept in index.json
+	if err := s1.Tag(ctx, desc, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+	if err := s2.Tag(ctx, desc, "v2"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+	s3, err := NewStore(root)
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	for _, tag := range []string{"v1", "v2"} {
+		got, err := s3.Resolve(ctx, tag)
+		if err != nil {
+			t.Fatalf("Store.Resolve(%q) error = %v", tag, err)
+		}
+		if got.Digest != desc.Digest {
+			t.Errorf("Store.Resolve(%q) = %v, want %v", tag, got.Digest, desc.Digest)
+		}
+	}
+}
+
+// corruptBlob overwrites the blob file with data.
+func corruptBlob(t *testing.T, s *Store, dgst digest.Digest, data []byte) {
+	t.Helper()
+	path := s.blobPath(dgst)
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal("os.Chmod() error =", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+}
+
+func TestStore_Verify(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	intact := pushBlob(t, s, []byte("intact"), time.Now())
+	corrupted := pushBlob(t, s, []byte("corrupted"), time.Now())
+	corruptBlob(t, s, corrupted.Digest, []byte("CORRUPTED"))
+
+	got := make(map[digest.Digest]error)
+	if err := s.Verify(ctx, func(b Blob, err error) error {
+		got[b.Digest] = err
+		return nil
+	}); err != nil {
+		t.Fatal("Store.Verify() error =", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Store.Verify() verified %d blobs, want 2", len(got))
+	}
+	if err := got[intact.Digest]; err != nil {
+		t.Errorf("Store.Verify() error = %v for intact blob", err)
+	}
+	if err := got[corrupted.Digest]; !errors.Is(err, content.ErrMismatchedDigest) {
+		t.Errorf("Store.Verify() error = %v for corrupted blob, want %v", err, content.ErrMismatchedDigest)
+	}
+}
+
+func TestStore_lockIngest_removesLockFile(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	dgst := digest.FromString("foo")
+	path := filepath.Join(s.root, lockDir, dgst.Algorithm().String(), dgst.Encoded())
+
+	unlock, err := s.lockIngest(ctx, dgst)
+	if err != nil {
+		t.Fatal("Store.lockIngest() error =", err)
+	}
+	// a waiting locker acquires the lock after the lock file is removed
+	locked := make(chan func() error)
+	go func() {
+		unlock, err := s.lockIngest(ctx, dgst)
+		if err != nil {
+			t.Error("Store.lockIngest() error =", err)
+		}
+		locked <- unlock
+	}()
+	time.Sleep(2 * lockRetryInterval)
+	if err := unlock(); err != nil {
+		t.Fatal("unlock() error =", err)
+	}
+	unlock = <-locked
+	if unlock == nil {
+		t.FailNow()
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("lock file of the waiting locker missing:", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatal("unlock() error =", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("os.Stat() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestStore_updateIndex_reload(t *testing.T) {
+	root := t.TempDir()
+	s1, err := NewStore(root)
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	s2, err := NewStore(root)
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	ctx := context.Background()
+	desc := pushBlob(t, s1, []byte("foo"), time.Now())
+
+	// index.json is not reloaded if unchanged
+	loaded := s1.current()
+	if err := s1.Tag(ctx, desc, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+	if s1.current() != loaded {
+		t.Error("Store.Tag() reloaded unchanged index.json")
+	}
+
+	// index.json is reloaded if changed by another store
+	if err := s2.Tag(ctx, desc, "v2"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+	if err := s1.Tag(ctx, desc, "v3"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+	for _, tag := range []string{"v1", "v2", "v3"} {
+		if _, err := s1.Resolve(ctx, tag); err != nil {
+			t.Errorf("Store.Resolve(%q) error = %v", tag, err)
+		}
+	}
+}
@@ -16,33 +16,124 @@ limitations under the License.
 package cache
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"net"
 	"sync"
+	"time"
 
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/registry"
 )
 
+// annotationResolved records when a tag was resolved from the origin.
+const annotationResolved = "land.oras.cache.resolved"
+
+// verifyBeforeReadLimit is the maximum size of cached content verified before
+// being returned.
+const verifyBeforeReadLimit = 4 * 1024 * 1024
+
+// ErrCorrupted is returned when the cached content fails verification. The
+// corrupted content is evicted from the cache.
+var ErrCorrupted = errors.New("corrupted content evicted from the local cache")
+
+// ErrNotCached is returned when content is requested in offline mode but is
+// not available in the cache.
+var ErrNotCached = errors.New("not found in the local cache")
+
+// ResolveOptions contains parameters for resolving references via the cache.
+type ResolveOptions struct {
+	// Namespace is the registry and repository of the origin, e.g.
+	// "localhost:5000/hello". Resolved tags are recorded in the cache under
+	// the namespace so that repositories sharing a cache do not collide.
+	// Tags are not recorded if Namespace is empty.
+	Namespace string
+	// Offline resolves references and fetches content from the cache only.
+	Offline bool
+	// TTL is the duration after being recorded within which a tag is
+	// resolved from the cache instead of the origin. If positive, recorded
+	// tags are also used when the origin is unreachable.
+	TTL time.Duration
+}
+
 type closer func() error
 
 func (fn closer) Close() error {
 	return fn()
 }
 
+// verifyReadCloser verifies the content as it is read. onCorrupted is called
+// once if the verification fails.
+type verifyReadCloser struct {
+	io.ReadCloser
+	vr          *content.VerifyReader
+	onCorrupted func(cause error) error
+	err         error
+}
+
+// Read reads the content and verifies it on EOF.
+func (r *verifyReadCloser) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.vr.Read(p)
+	if err == io.EOF {
+		if err = r.vr.Verify(); err == nil {
+			return n, io.EOF
+		}
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, content.ErrMismatchedDigest) || errors.Is(err, content.ErrTrailingData) {
+		// close before eviction as opened files cannot be removed on
+		// certain systems
+		r.ReadCloser.Close()
+		r.err = r.onCorrupted(err)
+		return n, r.err
+	}
+	return n, err
+}
+
+// Close closes the content.
+func (r *verifyReadCloser) Close() error {
+	if r.err != nil {
+		// closed on verification failure
+		return nil
+	}
+	return r.ReadCloser.Close()
+}
+
+// ingestLocker coordinates caching the same content by multiple processes.
+type ingestLocker interface {
+	// lockIngest blocks until no one else is caching the content identified
+	// by dgst, and locks it for caching.
+	lockIngest(ctx context.Context, dgst digest.Digest) (unlock func() error, err error)
+}
+
 // Cache target struct.
 type target struct {
 	oras.ReadOnlyTarget
 	cache content.Storage
+	opts  ResolveOptions
 }
 
 // New generates a new target storage with caching.
 func New(source oras.ReadOnlyTarget, cache content.Storage) oras.ReadOnlyTarget {
+	return NewWithResolveOptions(source, cache, ResolveOptions{})
+}
+
+// NewWithResolveOptions generates a new target storage with caching, where
+// references are resolved according to opts.
+func NewWithResolveOptions(source oras.ReadOnlyTarget, cache content.Storage, opts ResolveOptions) oras.ReadOnlyTarget {
 	t := &target{
 		ReadOnlyTarget: source,
 		cache:          cache,
+		opts:           opts,
 	}
 	if refFetcher, ok := source.(registry.ReferenceFetcher); ok {
 		return &referenceTarget{
@@ -55,21 +146,84 @@ func New(source oras.ReadOnlyTarget, cache content.Storage) oras.ReadOnlyTarget
 
 // Fetch fetches the content identified by the descriptor.
 func (t *target) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
-	rc, err := t.cache.Fetch(ctx, target)
+	rc, err := t.fetchCache(ctx, target)
 	if err == nil {
 		// Fetch from cache
 		return rc, nil
 	}
+	if t.opts.Offline {
+		if errors.Is(err, ErrCorrupted) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", target.Digest, ErrNotCached)
+	}
 
+	// wait for the content being cached by other processes
+	unlock, err := t.lockIngest(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if rc, err := t.fetchCache(ctx, target); err == nil {
+		unlock()
+		return rc, nil
+	}
 	if rc, err = t.ReadOnlyTarget.Fetch(ctx, target); err != nil {
+		unlock()
 		return nil, err
 	}
 
 	// Fetch from origin with caching
-	return t.cacheReadCloser(ctx, rc, target), nil
+	return t.cacheReadCloser(ctx, rc, target, nil, unlock), nil
+}
+
+// fetchCache fetches the content from the cache with its integrity verified.
+// Small content is verified before being returned so that corrupted content
+// can be fetched from the origin instead. Large content is verified as it is
+// read. Corrupted content is evicted from the cache in both cases.
+func (t *target) fetchCache(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := t.cache.Fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if target.Size <= verifyBeforeReadLimit {
+		data, err := content.ReadAll(rc, target)
+		rc.Close()
+		if err != nil {
+			return nil, t.evictCorrupted(ctx, target, err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return &verifyReadCloser{
+		ReadCloser: rc,
+		vr:         content.NewVerifyReader(rc, target),
+		onCorrupted: func(cause error) error {
+			return t.evictCorrupted(ctx, target, cause)
+		},
+	}, nil
 }
 
-func (t *target) cacheReadCloser(ctx context.Context, rc io.ReadCloser, target ocispec.Descriptor) io.ReadCloser {
+// evictCorrupted evicts corrupted content from the cache.
+func (t *target) evictCorrupted(ctx context.Context, target ocispec.Descriptor, cause error) error {
+	if deleter, ok := t.cache.(content.Deleter); ok {
+		if err := deleter.Delete(ctx, target); err != nil {
+			return fmt.Errorf("%s: %w: %v, failed to evict: %v", target.Digest, ErrCorrupted, cause, err)
+		}
+	}
+	return fmt.Errorf("%s: %w: %v", target.Digest, ErrCorrupted, cause)
+}
+
+// lockIngest locks the content for caching if supported by the cache, so
+// that the content is fetched from the origin by one process at a time.
+func (t *target) lockIngest(ctx context.Context, target ocispec.Descriptor) (unlock func() error, err error) {
+	if locker, ok := t.cache.(ingestLocker); ok {
+		return locker.lockIngest(ctx, target.Digest)
+	}
+	return func() error { return nil }, nil
+}
+
+// cacheReadCloser tees rc into the cache. onCached is called once the content
+// is completely cached and unlock is called on close.
+func (t *target) cacheReadCloser(ctx context.Context, rc io.ReadCloser, target ocispec.Descriptor, onCached func() error, unlock func() error) io.ReadCloser {
 	pr, pw := io.Pipe()
 	var wg sync.WaitGroup
 
@@ -80,6 +234,8 @@ func (t *target) cacheReadCloser(ctx context.Context, rc io.ReadCloser, target o
 		pushErr = t.cache.Push(ctx, target, pr)
 		if pushErr != nil {
 			pr.CloseWithError(pushErr)
+		} else if onCached != nil {
+			pushErr = onCached()
 		}
 	}()
 
@@ -89,6 +245,7 @@ func (t *target) cacheReadCloser(ctx context.Context, rc io.ReadCloser, target o
 	}{
 		Reader: io.TeeReader(rc, pw),
 		Closer: closer(func() error {
+			defer unlock()
 			rcErr := rc.Close()
 			if err := pw.Close(); err != nil {
 				return err
@@ -108,9 +265,132 @@ func (t *target) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, err
 	if err == nil && exists {
 		return true, nil
 	}
+	if t.opts.Offline {
+		return false, err
+	}
 	return t.ReadOnlyTarget.Exists(ctx, desc)
 }
 
+// Resolve resolves a reference to a descriptor. Tags are resolved from the
+// cache if allowed by the resolve options.
+func (t *target) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	desc, fresh, err := t.resolveCache(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if fresh {
+		return desc, nil
+	}
+
+	resolved, err := t.ReadOnlyTarget.Resolve(ctx, reference)
+	if err != nil {
+		if t.fallback(desc, err) {
+			return desc, nil
+		}
+		return ocispec.Descriptor{}, err
+	}
+	if exists, err := t.cache.Exists(ctx, resolved); err == nil && exists {
+		if err := t.recordTag(ctx, reference, resolved); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	return resolved, nil
+}
+
+// resolveCache resolves reference from the cache. fresh is true if the
+// resolved descriptor can be used without contacting the origin. Otherwise,
+// the returned descriptor, if not empty, can be used as a fallback.
+func (t *target) resolveCache(ctx context.Context, reference string) (desc ocispec.Descriptor, fresh bool, err error) {
+	if _, err := digest.Parse(reference); err == nil {
+		if t.opts.Offline {
+			resolver, ok := t.cache.(content.Resolver)
+			if !ok {
+				return ocispec.Descriptor{}, false, fmt.Errorf("%s: %w", reference, ErrNotCached)
+			}
+			desc, err := resolver.Resolve(ctx, reference)
+			if err != nil {
+				return ocispec.Descriptor{}, false, fmt.Errorf("%s: %w", reference, ErrNotCached)
+			}
+			return desc, true, nil
+		}
+		return ocispec.Descriptor{}, false, nil
+	}
+
+	tagResolver, ok := t.cache.(content.TagResolver)
+	if !ok || t.opts.Namespace == "" {
+		if t.opts.Offline {
+			return ocispec.Descriptor{}, false, fmt.Errorf("%s: %w", reference, ErrNotCached)
+		}
+		return ocispec.Descriptor{}, false, nil
+	}
+	cacheRef := t.cacheReference(reference)
+	desc, err = tagResolver.Resolve(ctx, cacheRef)
+	if err == nil {
+		// the tagged content may have been evicted
+		if exists, existsErr := t.cache.Exists(ctx, desc); existsErr != nil || !exists {
+			err = ErrNotCached
+		}
+	}
+	if err != nil {
+		if t.opts.Offline {
+			return ocispec.Descriptor{}, false, fmt.Errorf("tag %q has never been cached: %w", cacheRef, ErrNotCached)
+		}
+		return ocispec.Descriptor{}, false, nil
+	}
+
+	resolvedAt, _ := time.Parse(time.RFC3339, desc.Annotations[annotationResolved])
+	fresh = t.opts.Offline || (t.opts.TTL > 0 && time.Since(resolvedAt) < t.opts.TTL)
+	return stripCacheAnnotations(desc), fresh, nil
+}
+
+// fallback returns true if the cached descriptor should be used since the
+// origin is unreachable.
+func (t *target) fallback(cached ocispec.Descriptor, err error) bool {
+	if t.opts.TTL <= 0 || cached.Digest == "" {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// recordTag records the descriptor resolved from a tag in the cache.
+func (t *target) recordTag(ctx context.Context, reference string, desc ocispec.Descriptor) error {
+	if _, err := digest.Parse(reference); err == nil {
+		return nil
+	}
+	tagger, ok := t.cache.(content.Tagger)
+	if !ok || t.opts.Namespace == "" {
+		return nil
+	}
+	annotations := maps.Clone(desc.Annotations)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[annotationResolved] = time.Now().UTC().Format(time.RFC3339)
+	desc.Annotations = annotations
+	return tagger.Tag(ctx, desc, t.cacheReference(reference))
+}
+
+// cacheReference returns the reference used to record tag in the cache.
+func (t *target) cacheReference(tag string) string {
+	return t.opts.Namespace + ":" + tag
+}
+
+// stripCacheAnnotations removes annotations added by the cache.
+func stripCacheAnnotations(desc ocispec.Descriptor) ocispec.Descriptor {
+	if _, ok := desc.Annotations[annotationResolved]; !ok {
+		return desc
+	}
+	annotations := maps.Clone(desc.Annotations)
+	delete(annotations, annotationResolved)
+	delete(annotations, ocispec.AnnotationRefName)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	desc.Annotations = annotations
+	return desc
+}
+
 // Cache referenceTarget struct.
 type referenceTarget struct {
 	*target
@@ -119,35 +399,65 @@ type referenceTarget struct {
 
 // FetchReference fetches the content identified by the reference from the
 // remote and cache the fetched content.
-// Cached content will only be read via Fetch, FetchReference will always fetch
-// From origin.
+// Unless allowed by the resolve options, FetchReference always resolves the
+// reference from the origin while the content will be read from the cache if
+// available.
 func (t *referenceTarget) FetchReference(ctx context.Context, reference string) (ocispec.Descriptor, io.ReadCloser, error) {
+	cached, fresh, err := t.resolveCache(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if fresh {
+		rc, err := t.fetchCache(ctx, cached)
+		if err == nil {
+			return cached, rc, nil
+		}
+		if t.opts.Offline {
+			return ocispec.Descriptor{}, nil, err
+		}
+		// corrupted content is fetched from the origin again
+	}
+
 	target, rc, err := t.ReferenceFetcher.FetchReference(ctx, reference)
 	if err != nil {
+		if t.fallback(cached, err) {
+			rc, err := t.fetchCache(ctx, cached)
+			if err != nil {
+				return ocispec.Descriptor{}, nil, err
+			}
+			return cached, rc, nil
+		}
 		return ocispec.Descriptor{}, nil, err
 	}
 
 	// skip caching if the content already exists in cache
-	exists, err := t.cache.Exists(ctx, target)
+	cachedRC, err := t.fetchCache(ctx, target)
+	unlock := func() error { return nil }
 	if err != nil {
-		return ocispec.Descriptor{}, nil, err
+		// wait for the content being cached by other processes
+		if unlock, err = t.lockIngest(ctx, target); err != nil {
+			rc.Close()
+			return ocispec.Descriptor{}, nil, err
+		}
+		if cachedRC, err = t.fetchCache(ctx, target); err == nil {
+			unlock()
+		}
 	}
-	if exists {
-		err = rc.Close()
-		if err != nil {
+	if err == nil {
+		// no need to do tee'd push
+		if err := rc.Close(); err != nil {
+			cachedRC.Close()
 			return ocispec.Descriptor{}, nil, err
 		}
-
-		// get rc from the cache
-		rc, err = t.cache.Fetch(ctx, target)
-		if err != nil {
+		if err := t.recordTag(ctx, reference, target); err != nil {
+			cachedRC.Close()
 			return ocispec.Descriptor{}, nil, err
 		}
-
-		// no need to do tee'd push
-		return target, rc, nil
+		return target, cachedRC, nil
 	}
 
 	// Fetch from origin with caching
-	return target, t.cacheReadCloser(ctx, rc, target), nil
+	return target, t.cacheReadCloser(ctx, rc, target, func() error {
+		return t.recordTag(ctx, reference, target)
+	}, unlock), nil
 }
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"context"
 	_ "crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,8 +27,10 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -270,3 +273,245 @@ func TestProxy_fetchReference(t *testing.T) {
 		t.Errorf("unexpected number of successful requests: %d, want %d", successCount, wantSuccessCount)
 	}
 }
+
+func TestProxy_fetchReference_resolveOptions(t *testing.T) {
+	// mocked variables
+	blob := []byte("{}")
+	repoName := "test/repo"
+	tagName := "test-tag"
+	mediaType := ocispec.MediaTypeImageManifest
+	digest := digest.FromBytes(blob)
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      int64(len(blob)),
+	}
+
+	// mocked remote registry
+	var requestCount int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requestCount, 1)
+		if r.Method == http.MethodGet && r.URL.Path == fmt.Sprintf("/v2/%s/manifests/%s", repoName, tagName) {
+			w.Header().Set("Content-Type", mediaType)
+			w.Header().Set("Docker-Content-Digest", digest.String())
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write(blob); err != nil {
+				t.Errorf("Error writing blobs: %v", err)
+			}
+			return
+		}
+		t.Errorf("unexpected access: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+	uri, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid test http server: %v", err)
+	}
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s:%s", uri.Host, repoName, tagName))
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	repo.PlainHTTP = true
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	namespace := uri.Host + "/" + repoName
+	ctx := context.Background()
+	fetchReference := func(opts ResolveOptions) (ocispec.Descriptor, error) {
+		t.Helper()
+		p := NewWithResolveOptions(repo, store, opts)
+		gotDesc, rc, err := p.(registry.ReferenceFetcher).FetchReference(ctx, tagName)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		got, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal("io.ReadAll() error =", err)
+		}
+		if !bytes.Equal(got, blob) {
+			t.Errorf("ReferenceTarget.FetchReference() = %v, want %v", got, blob)
+		}
+		return gotDesc, rc.Close()
+	}
+
+	// offline fetch fails if the tag has never been cached
+	if _, err := fetchReference(ResolveOptions{Namespace: namespace, Offline: true}); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("ReferenceTarget.FetchReference() error = %v, want %v", err, ErrNotCached)
+	}
+	if requestCount != 0 {
+		t.Fatalf("unexpected number of requests: %d, want 0", requestCount)
+	}
+
+	// online fetch records the tag
+	gotDesc, err := fetchReference(ResolveOptions{Namespace: namespace})
+	if err != nil {
+		t.Fatal("ReferenceTarget.FetchReference() error =", err)
+	}
+	if !reflect.DeepEqual(gotDesc, desc) {
+		t.Fatalf("ReferenceTarget.FetchReference() got %v, want %v", gotDesc, desc)
+	}
+	if requestCount != 1 {
+		t.Fatalf("unexpected number of requests: %d, want 1", requestCount)
+	}
+
+	// offline fetch and resolve are served from the cache
+	gotDesc, err = fetchReference(ResolveOptions{Namespace: namespace, Offline: true})
+	if err != nil {
+		t.Fatal("ReferenceTarget.FetchReference() error =", err)
+	}
+	if !reflect.DeepEqual(gotDesc, desc) {
+		t.Fatalf("ReferenceTarget.FetchReference() got %v, want %v", gotDesc, desc)
+	}
+	offline := NewWithResolveOptions(repo, store, ResolveOptions{Namespace: namespace, Offline: true})
+	gotDesc, err = offline.Resolve(ctx, tagName)
+	if err != nil {
+		t.Fatal("ReferenceTarget.Resolve() error =", err)
+	}
+	if !reflect.DeepEqual(gotDesc, desc) {
+		t.Fatalf("ReferenceTarget.Resolve() got %v, want %v", gotDesc, desc)
+	}
+	if _, err := offline.Resolve(ctx, "unknown"); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("ReferenceTarget.Resolve() error = %v, want %v", err, ErrNotCached)
+	}
+
+	// fresh tags are served from the cache
+	if _, err := fetchReference(ResolveOptions{Namespace: namespace, TTL: time.Hour}); err != nil {
+		t.Fatal("ReferenceTarget.FetchReference() error =", err)
+	}
+	if requestCount != 1 {
+		t.Fatalf("unexpected number of requests: %d, want 1", requestCount)
+	}
+
+	// stale tags are resolved from the origin
+	if _, err := fetchReference(ResolveOptions{Namespace: namespace, TTL: time.Nanosecond}); err != nil {
+		t.Fatal("ReferenceTarget.FetchReference() error =", err)
+	}
+	if requestCount != 2 {
+		t.Fatalf("unexpected number of requests: %d, want 2", requestCount)
+	}
+
+	// stale tags are served from the cache if the origin is unreachable
+	ts.Close()
+	gotDesc, err = fetchReference(ResolveOptions{Namespace: namespace, TTL: time.Nanosecond})
+	if err != nil {
+		t.Fatal("ReferenceTarget.FetchReference() error =", err)
+	}
+	if !reflect.DeepEqual(gotDesc, desc) {
+		t.Fatalf("ReferenceTarget.FetchReference() got %v, want %v", gotDesc, desc)
+	}
+	if _, err := fetchReference(ResolveOptions{Namespace: namespace}); err == nil {
+		t.Fatal("ReferenceTarget.FetchReference() error = nil, want error without TTL")
+	}
+}
+
+type countingTarget struct {
+	oras.ReadOnlyTarget
+	fetches atomic.Int64
+}
+
+func (t *countingTarget) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	t.fetches.Add(1)
+	return t.ReadOnlyTarget.Fetch(ctx, target)
+}
+
+func TestProxy_fetch_sharedCache(t *testing.T) {
+	blob := []byte("hello world")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	ctx := context.Background()
+	base := memory.New()
+	if err := base.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal("memory.Push() error =", err)
+	}
+	origin := &countingTarget{ReadOnlyTarget: base}
+
+	// each target simulates a process with its own view of the cache
+	root := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		store, err := NewStore(root)
+		if err != nil {
+			t.Fatal("NewStore() error =", err)
+		}
+		p := New(origin, store)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := content.FetchAll(ctx, p, desc)
+			if err != nil {
+				t.Error("Proxy.Fetch() error =", err)
+				return
+			}
+			if !bytes.Equal(got, blob) {
+				t.Errorf("Proxy.Fetch() = %v, want %v", got, blob)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := origin.fetches.Load(); got != 1 {
+		t.Errorf("unexpected number of fetches from origin: %d, want 1", got)
+	}
+}
+
+func TestProxy_fetch_corrupted(t *testing.T) {
+	ctx := context.Background()
+	small := []byte("hello world")
+	large := bytes.Repeat([]byte("x"), verifyBeforeReadLimit+1)
+	base := memory.New()
+	var descs []ocispec.Descriptor
+	for _, blob := range [][]byte{small, large} {
+		desc := content.NewDescriptorFromBytes("test", blob)
+		if err := base.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+			t.Fatal("memory.Push() error =", err)
+		}
+		descs = append(descs, desc)
+	}
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() error =", err)
+	}
+	p := New(base, store)
+	for _, desc := range descs {
+		if _, err := content.FetchAll(ctx, p, desc); err != nil {
+			t.Fatal("Proxy.Fetch() error =", err)
+		}
+	}
+
+	// corrupted small content is fetched from the origin again
+	smallDesc := descs[0]
+	corruptBlob(t, store, smallDesc.Digest, []byte("HELLO WORLD"))
+	got, err := content.FetchAll(ctx, p, smallDesc)
+	if err != nil {
+		t.Fatal("Proxy.Fetch() error =", err)
+	}
+	if !bytes.Equal(got, small) {
+		t.Errorf("Proxy.Fetch() = %q, want %q", got, small)
+	}
+	if err := store.verify(Blob{Digest: smallDesc.Digest}); err != nil {
+		t.Errorf("corrupted content is not healed: %v", err)
+	}
+
+	// corrupted large content is evicted once read
+	largeDesc := descs[1]
+	corruptBlob(t, store, largeDesc.Digest, large[1:])
+	if _, err := content.FetchAll(ctx, p, largeDesc); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("Proxy.Fetch() error = %v, want %v", err, ErrCorrupted)
+	}
+	exists, err := store.Exists(ctx, largeDesc)
+	if err != nil {
+		t.Fatal("Store.Exists() error =", err)
+	}
+	if exists {
+		t.Errorf("corrupted content %s is not evicted", largeDesc.Digest)
+	}
+	got, err = content.FetchAll(ctx, p, largeDesc)
+	if err != nil {
+		t.Fatal("Proxy.Fetch() error =", err)
+	}
+	if !bytes.Equal(got, large) {
+		t.Error("Proxy.Fetch() returned unexpected content")
+	}
+}
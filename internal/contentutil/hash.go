@@ -0,0 +1,43 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contentutil
+
+import (
+	"encoding"
+	"hash"
+)
+
+// MarshalHash returns the state of h, or nil if it cannot be marshaled.
+func MarshalHash(h hash.Hash) []byte {
+	marshaler, ok := h.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil
+	}
+	state, err := marshaler.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return state
+}
+
+// UnmarshalHash restores the state of h, reporting whether it succeeds.
+func UnmarshalHash(h hash.Hash, state []byte) bool {
+	unmarshaler, ok := h.(encoding.BinaryUnmarshaler)
+	if !ok || len(state) == 0 {
+		return false
+	}
+	return unmarshaler.UnmarshalBinary(state) == nil
+}
@@ -16,8 +16,12 @@ limitations under the License.
 package descriptor
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/internal/archive"
 	"oras.land/oras/internal/docker"
 )
 
@@ -67,7 +71,14 @@ func Plain(desc ocispec.Descriptor) ocispec.Descriptor {
 }
 
 // GetTitleOrMediaType gets a descriptor name using either title or media type.
+// Chunks split from a file are named after the file and the chunk position.
 func GetTitleOrMediaType(desc ocispec.Descriptor) (name string, isTitle bool) {
+	if archive.IsChunk(desc) {
+		index, err := strconv.Atoi(desc.Annotations[archive.AnnotationChunkIndex])
+		if err == nil {
+			return fmt.Sprintf("%s (%d/%s)", desc.Annotations[archive.AnnotationChunkTitle], index+1, desc.Annotations[archive.AnnotationChunkCount]), true
+		}
+	}
 	name, ok := desc.Annotations[ocispec.AnnotationTitle]
 	if !ok {
 		return desc.MediaType, false
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/internal/archive"
 	"oras.land/oras/internal/descriptor"
 )
 
@@ -94,6 +95,23 @@ func TestDescriptor_GetTitleOrMediaType(t *testing.T) {
 	if true != isTitle {
 		t.Fatalf("GetTitleOrMediaType() got %v, want %v", isTitle, false)
 	}
+
+	expected = "model.bin (2/3)"
+	chunkDesc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Annotations: map[string]string{
+			archive.AnnotationChunkTitle: "model.bin",
+			archive.AnnotationChunkIndex: "1",
+			archive.AnnotationChunkCount: "3",
+		},
+	}
+	name, isTitle = descriptor.GetTitleOrMediaType(chunkDesc)
+	if expected != name {
+		t.Fatalf("GetTitleOrMediaType() got %v, want %v", name, expected)
+	}
+	if true != isTitle {
+		t.Fatalf("GetTitleOrMediaType() got %v, want %v", isTitle, true)
+	}
 }
 
 func TestDescriptor_GenerateContentKey(t *testing.T) {
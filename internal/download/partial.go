@@ -0,0 +1,314 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package download implements resumable blob downloads.
+package download
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras/internal/contentutil"
+)
+
+// saveInterval is the number of downloaded bytes after which the progress is
+// recorded in the sidecar file.
+const saveInterval = 4 * 1024 * 1024
+
+// sidecarSuffix is the suffix of the file recording the progress of a partial
+// download.
+const sidecarSuffix = ".json"
+
+// progress is the progress of a partial download recorded in the sidecar
+// file.
+type progress struct {
+	// Digest is the digest of the blob.
+	Digest digest.Digest `json:"digest"`
+	// Size is the size of the blob.
+	Size int64 `json:"size"`
+	// Offset is the number of bytes downloaded.
+	Offset int64 `json:"offset"`
+	// HashState is the marshaled state of the hash of the first Offset
+	// bytes, if supported by the digest algorithm.
+	HashState []byte `json:"hashState,omitempty"`
+}
+
+// Fetch fetches the content identified by desc from fetcher, keeping the
+// downloaded bytes in the file at path and the progress in a sidecar file
+// beside it. If the download was interrupted, it is resumed by seeking the
+// fetched content, which issues HTTP Range requests for remote repositories.
+// The content is downloaded from the beginning if seeking is not supported.
+// The partial files are removed once the content is fully downloaded and
+// verified.
+func Fetch(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor, path string) (io.ReadCloser, error) {
+	return fetch(ctx, fetcher, desc, path, SegmentOptions{})
+}
+
+// fetch fetches the content like Fetch, where the rest of the content is
+// fetched in segments if it is large enough according to segOpts.
+func fetch(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor, path string, segOpts SegmentOptions) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+	fp, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	r := &reader{
+		file:   fp,
+		path:   path,
+		desc:   desc,
+		hasher: desc.Digest.Algorithm().Hash(),
+	}
+	if err := r.restore(); err != nil {
+		fp.Close()
+		return nil, err
+	}
+
+	var rc io.ReadCloser
+	if segOpts.enabled(desc.Size - r.offset) {
+		rc, r.inPlace, err = fetchSegments(ctx, segOpts, fp, desc, r.offset)
+		if err == nil && !r.inPlace {
+			// the registry responds the full content
+			err = r.reset()
+		}
+	} else {
+		rc, err = r.fetchRest(ctx, fetcher)
+	}
+	if err != nil {
+		if rc != nil {
+			rc.Close()
+		}
+		r.save()
+		fp.Close()
+		return nil, err
+	}
+	r.body = rc
+	r.prefix = io.NewSectionReader(fp, 0, r.offset)
+	r.saved = r.offset
+	return r, nil
+}
+
+// fetchRest fetches the content from the offset by seeking. The downloaded
+// bytes are discarded if seeking is not supported.
+func (r *reader) fetchRest(ctx context.Context, fetcher content.Fetcher) (io.ReadCloser, error) {
+	rc, err := fetcher.Fetch(ctx, r.desc)
+	if err != nil || r.offset == 0 {
+		return rc, err
+	}
+	seeker, ok := rc.(io.Seeker)
+	if ok {
+		if _, err = seeker.Seek(r.offset, io.SeekStart); err == nil {
+			return rc, nil
+		}
+		// the content cannot be fetched from the offset
+		rc.Close()
+		if rc, err = fetcher.Fetch(ctx, r.desc); err != nil {
+			return nil, err
+		}
+	}
+	return rc, r.reset()
+}
+
+// reader reads the downloaded bytes from the partial file, followed by the
+// rest of the content fetched from the source.
+type reader struct {
+	file   *os.File
+	path   string
+	desc   ocispec.Descriptor
+	hasher hash.Hash
+	prefix io.Reader
+	body   io.ReadCloser
+	offset int64
+	saved  int64
+	done   bool
+	// inPlace is true if body reads back the bytes already written to
+	// file.
+	inPlace bool
+}
+
+// Read reads the content.
+func (r *reader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	if r.prefix != nil {
+		n, err := r.prefix.Read(p)
+		if err != io.EOF {
+			return n, err
+		}
+		r.prefix = nil
+		if n > 0 {
+			return n, nil
+		}
+	}
+
+	n, err := r.body.Read(p)
+	if n > 0 {
+		if r.offset+int64(n) > r.desc.Size {
+			return 0, content.ErrTrailingData
+		}
+		if !r.inPlace {
+			if _, err := r.file.Write(p[:n]); err != nil {
+				return 0, err
+			}
+		}
+		r.hasher.Write(p[:n])
+		r.offset += int64(n)
+		if r.offset-r.saved >= saveInterval {
+			r.save()
+		}
+	}
+	switch {
+	case err == io.EOF:
+		if err := r.finish(); err != nil {
+			return n, err
+		}
+	case err != nil:
+		r.save()
+	}
+	return n, err
+}
+
+// Close closes the reader. The progress is recorded if the content is not
+// fully read.
+func (r *reader) Close() error {
+	err := r.body.Close()
+	if !r.done {
+		r.save()
+		if closeErr := r.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// restore restores the progress recorded in the sidecar file. The partial
+// download is reset if the progress is not usable.
+func (r *reader) restore() error {
+	var p progress
+	data, err := os.ReadFile(r.path + sidecarSuffix)
+	if err != nil || json.Unmarshal(data, &p) != nil || p.Digest != r.desc.Digest || p.Size != r.desc.Size || p.Offset < 0 || p.Offset > r.desc.Size {
+		return r.reset()
+	}
+	fi, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() < p.Offset {
+		return r.reset()
+	}
+	// drop the bytes downloaded after the progress is recorded
+	if err := r.file.Truncate(p.Offset); err != nil {
+		return err
+	}
+	// hash the downloaded bytes again to detect corruption of the partial
+	// file since the progress is recorded
+	if _, err := io.Copy(r.hasher, io.NewSectionReader(r.file, 0, p.Offset)); err != nil {
+		return err
+	}
+	if p.HashState != nil && !bytes.Equal(contentutil.MarshalHash(r.hasher), p.HashState) {
+		return r.reset()
+	}
+	if _, err := r.file.Seek(p.Offset, io.SeekStart); err != nil {
+		return err
+	}
+	r.offset = p.Offset
+	return nil
+}
+
+// reset discards the downloaded bytes.
+func (r *reader) reset() error {
+	if err := r.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := r.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r.hasher.Reset()
+	r.offset = 0
+	return nil
+}
+
+// save records the progress in the sidecar file. Failures are ignored as the
+// download can be restarted anyway.
+func (r *reader) save() {
+	if r.offset == 0 {
+		return
+	}
+	data, err := json.Marshal(progress{
+		Digest:    r.desc.Digest,
+		Size:      r.desc.Size,
+		Offset:    r.offset,
+		HashState: contentutil.MarshalHash(r.hasher),
+	})
+	if err != nil {
+		return
+	}
+	// write to a temporary file first so that the record is never broken
+	dir := filepath.Dir(r.path)
+	fp, err := os.CreateTemp(dir, filepath.Base(r.path)+"_*")
+	if err != nil {
+		return
+	}
+	tmp := fp.Name()
+	_, err = fp.Write(data)
+	if closeErr := fp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, r.path+sidecarSuffix)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return
+	}
+	r.saved = r.offset
+}
+
+// finish verifies the downloaded content and removes the partial files.
+func (r *reader) finish() error {
+	r.done = true
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if r.offset != r.desc.Size {
+		r.save()
+		return io.ErrUnexpectedEOF
+	}
+	if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	if err := os.Remove(r.path + sidecarSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if got := digest.NewDigest(r.desc.Digest.Algorithm(), r.hasher); got != r.desc.Digest {
+		return fmt.Errorf("%s: %w: got %s", r.desc.Digest, content.ErrMismatchedDigest, got)
+	}
+	return nil
+}
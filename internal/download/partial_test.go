@@ -0,0 +1,195 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+// interruptedFetcher fetches content which fails after limit bytes.
+type interruptedFetcher struct {
+	content.Fetcher
+	limit int64
+}
+
+func (f *interruptedFetcher) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := f.Fetcher.Fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(io.LimitReader(rc, f.limit), iotest.ErrReader(errInterrupted)),
+		Closer: rc,
+	}, nil
+}
+
+// newTestRepository serves blob and records the Range headers of the
+// requests. Range requests are ignored if supportRange is false.
+func newTestRepository(t *testing.T, blob []byte, supportRange bool) (*remote.Repository, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, "/v2/test/blobs/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		if !supportRange {
+			w.Write(blob)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+	}))
+	t.Cleanup(ts.Close)
+	uri, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid test http server: %v", err)
+	}
+	repo, err := remote.NewRepository(uri.Host + "/test")
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	repo.PlainHTTP = true
+	return repo, &ranges
+}
+
+func TestFetch_resume(t *testing.T) {
+	blob := []byte("hello world, this is a resumable download")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	tests := []struct {
+		name         string
+		supportRange bool
+		wantRange    string
+	}{
+		{
+			name:         "range supported",
+			supportRange: true,
+			wantRange:    fmt.Sprintf("bytes=%d-%d", 10, len(blob)-1),
+		},
+		{
+			name:         "range ignored",
+			supportRange: false,
+			wantRange:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ranges := newTestRepository(t, blob, tt.supportRange)
+			path := filepath.Join(t.TempDir(), "blob.partial")
+			ctx := context.Background()
+
+			// the first download is interrupted after 10 bytes
+			rc, err := Fetch(ctx, &interruptedFetcher{Fetcher: repo, limit: 10}, desc, path)
+			if err != nil {
+				t.Fatal("Fetch() error =", err)
+			}
+			if _, err := io.ReadAll(rc); !errors.Is(err, errInterrupted) {
+				t.Fatalf("io.ReadAll() error = %v, want %v", err, errInterrupted)
+			}
+			rc.Close()
+			if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, blob[:10]) {
+				t.Fatalf("partial file = %q, %v, want %q", data, err, blob[:10])
+			}
+
+			// the second download resumes
+			*ranges = nil
+			rc, err = Fetch(ctx, repo, desc, path)
+			if err != nil {
+				t.Fatal("Fetch() error =", err)
+			}
+			got, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal("io.ReadAll() error =", err)
+			}
+			rc.Close()
+			if !bytes.Equal(got, blob) {
+				t.Errorf("Fetch() = %q, want %q", got, blob)
+			}
+			if last := (*ranges)[len(*ranges)-1]; last != tt.wantRange {
+				t.Errorf("Range = %q, want %q", last, tt.wantRange)
+			}
+			for _, p := range []string{path, path + sidecarSuffix} {
+				if _, err := os.Stat(p); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("partial file %s is not removed: %v", p, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFetch_corruptedPartial(t *testing.T) {
+	blob := []byte("hello world, this is a resumable download")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	repo, ranges := newTestRepository(t, blob, true)
+	path := filepath.Join(t.TempDir(), "blob.partial")
+	ctx := context.Background()
+
+	rc, err := Fetch(ctx, &interruptedFetcher{Fetcher: repo, limit: 10}, desc, path)
+	if err != nil {
+		t.Fatal("Fetch() error =", err)
+	}
+	io.ReadAll(rc)
+	rc.Close()
+	// tamper the partial file
+	if err := os.WriteFile(path, []byte("HELLO WORL"), 0666); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+
+	// the download restarts
+	*ranges = nil
+	rc, err = Fetch(ctx, repo, desc, path)
+	if err != nil {
+		t.Fatal("Fetch() error =", err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal("io.ReadAll() error =", err)
+	}
+	if !bytes.Equal(got, blob) {
+		t.Errorf("Fetch() = %q, want %q", got, blob)
+	}
+	if want := []string{""}; !reflect.DeepEqual(*ranges, want) {
+		t.Errorf("Range = %q, want %q", *ranges, want)
+	}
+}
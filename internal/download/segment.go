@@ -0,0 +1,235 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// SegmentOptions configures segmented downloads, where large blobs are split
+// into byte ranges fetched in parallel.
+type SegmentOptions struct {
+	// Repository serves the byte ranges of the blobs. Segmented downloads
+	// are disabled if Repository is nil.
+	Repository *remote.Repository
+	// Threshold is the size above which blobs are downloaded in segments.
+	Threshold int64
+	// Count is the number of segments fetched in parallel.
+	Count int
+}
+
+// enabled reports whether the remaining size of a blob is large enough to be
+// downloaded in segments.
+func (opts SegmentOptions) enabled(remaining int64) bool {
+	return opts.Repository != nil && opts.Count > 1 && opts.Threshold > 0 && remaining > opts.Threshold
+}
+
+// segment is a byte range of a blob.
+type segment struct {
+	start   int64
+	end     int64
+	written int64
+}
+
+// segmentedReader fetches the segments of a blob in parallel into the
+// partial file, and reads them back in order.
+type segmentedReader struct {
+	file     *os.File
+	segments []*segment
+	current  int
+	pos      int64
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	cond     *sync.Cond
+	err      error
+}
+
+// fetchSegments fetches desc from offset in segments, which are written to
+// file at their offsets. The first segment is requested before returning, and
+// ranged is false if the registry ignores the range, in which case the full
+// content is returned instead.
+func fetchSegments(ctx context.Context, opts SegmentOptions, file *os.File, desc ocispec.Descriptor, offset int64) (rc io.ReadCloser, ranged bool, err error) {
+	size := (desc.Size - offset + int64(opts.Count) - 1) / int64(opts.Count)
+	var segments []*segment
+	for start := offset; start < desc.Size; start += size {
+		segments = append(segments, &segment{
+			start: start,
+			end:   min(start+size, desc.Size),
+		})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	first, err := fetchRange(ctx, opts.Repository, desc, segments[0].start, segments[0].end)
+	if err != nil {
+		cancel()
+		return nil, false, err
+	}
+	switch first.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return &cancelReadCloser{ReadCloser: first.Body, cancel: cancel}, false, nil
+	default:
+		first.Body.Close()
+		cancel()
+		return nil, false, fmt.Errorf("%s %q: unexpected status code %d", first.Request.Method, first.Request.URL, first.StatusCode)
+	}
+
+	s := &segmentedReader{
+		file:     file,
+		segments: segments,
+		pos:      offset,
+		cancel:   cancel,
+	}
+	s.cond = sync.NewCond(&s.mu)
+	s.wg.Add(len(segments))
+	go s.fetch(ctx, opts.Repository, desc, segments[0], first.Body)
+	for _, seg := range segments[1:] {
+		go s.fetch(ctx, opts.Repository, desc, seg, nil)
+	}
+	return s, true, nil
+}
+
+// fetch fetches the segment into the file. The segment is requested if body
+// is nil.
+func (s *segmentedReader) fetch(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor, seg *segment, body io.ReadCloser) {
+	defer s.wg.Done()
+	if body == nil {
+		resp, err := fetchRange(ctx, repo, desc, seg.start, seg.end)
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		if resp.StatusCode != http.StatusPartialContent {
+			resp.Body.Close()
+			s.fail(fmt.Errorf("%s %q: unexpected status code %d", resp.Request.Method, resp.Request.URL, resp.StatusCode))
+			return
+		}
+		body = resp.Body
+	}
+	defer body.Close()
+
+	buf := make([]byte, 32*1024)
+	for written := int64(0); written < seg.end-seg.start; {
+		n, err := body.Read(buf[:min(int64(len(buf)), seg.end-seg.start-written)])
+		if n > 0 {
+			if _, err := s.file.WriteAt(buf[:n], seg.start+written); err != nil {
+				s.fail(err)
+				return
+			}
+			written += int64(n)
+			s.mu.Lock()
+			seg.written = written
+			s.cond.Broadcast()
+			s.mu.Unlock()
+		}
+		if err == io.EOF && written < seg.end-seg.start {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil && err != io.EOF {
+			s.fail(err)
+			return
+		}
+	}
+}
+
+// fail records the first error and stops fetching the other segments.
+func (s *segmentedReader) fail(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.cond.Broadcast()
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Read reads the fetched segments in order.
+func (s *segmentedReader) Read(p []byte) (int, error) {
+	for s.current < len(s.segments) && s.pos >= s.segments[s.current].end {
+		s.current++
+	}
+	if s.current == len(s.segments) {
+		return 0, io.EOF
+	}
+	seg := s.segments[s.current]
+
+	// wait for the segment to be fetched at the current position
+	s.mu.Lock()
+	for seg.start+seg.written <= s.pos && s.err == nil {
+		s.cond.Wait()
+	}
+	available, err := seg.start+seg.written-s.pos, s.err
+	s.mu.Unlock()
+	if available <= 0 {
+		return 0, err
+	}
+
+	n, err := s.file.ReadAt(p[:min(int64(len(p)), available)], s.pos)
+	s.pos += int64(n)
+	if err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// Close stops fetching the segments.
+func (s *segmentedReader) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// cancelReadCloser cancels the context on close.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the reader and cancels the context.
+func (rc *cancelReadCloser) Close() error {
+	defer rc.cancel()
+	return rc.ReadCloser.Close()
+}
+
+// fetchRange requests the bytes of desc in the range [start, end).
+func fetchRange(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor, start, end int64) (*http.Response, error) {
+	ctx = auth.AppendRepositoryScope(ctx, repo.Reference, auth.ActionPull)
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s/v2/%s/blobs/%s", scheme, repo.Reference.Host(), repo.Reference.Repository, desc.Digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	var client remote.Client = auth.DefaultClient
+	if repo.Client != nil {
+		client = repo.Client
+	}
+	return client.Do(req)
+}
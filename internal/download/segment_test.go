@@ -0,0 +1,129 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"oras.land/oras-go/v2/content"
+)
+
+func TestFetch_segments(t *testing.T) {
+	blob := make([]byte, 100*1024)
+	if _, err := rand.Read(blob); err != nil {
+		t.Fatal("rand.Read() error =", err)
+	}
+	desc := content.NewDescriptorFromBytes("test", blob)
+	tests := []struct {
+		name         string
+		supportRange bool
+		offset       int64
+		wantRanges   []string
+	}{
+		{
+			name:         "segmented",
+			supportRange: true,
+			wantRanges: []string{
+				"bytes=0-25599",
+				"bytes=25600-51199",
+				"bytes=51200-76799",
+				"bytes=76800-102399",
+			},
+		},
+		{
+			name:         "resumed",
+			supportRange: true,
+			offset:       2400,
+			wantRanges: []string{
+				"bytes=2400-27399",
+				"bytes=27400-52399",
+				"bytes=52400-77399",
+				"bytes=77400-102399",
+			},
+		},
+		{
+			name:         "range ignored",
+			supportRange: false,
+			wantRanges:   []string{"bytes=0-25599"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ranges := newTestRepository(t, blob, tt.supportRange)
+			path := filepath.Join(t.TempDir(), "blob.partial")
+			ctx := context.Background()
+			if tt.offset > 0 {
+				rc, err := Fetch(ctx, &interruptedFetcher{Fetcher: repo, limit: tt.offset}, desc, path)
+				if err != nil {
+					t.Fatal("Fetch() error =", err)
+				}
+				io.ReadAll(rc)
+				rc.Close()
+				*ranges = nil
+			}
+
+			rc, err := fetch(ctx, repo, desc, path, SegmentOptions{
+				Repository: repo,
+				Threshold:  1024,
+				Count:      4,
+			})
+			if err != nil {
+				t.Fatal("fetch() error =", err)
+			}
+			got, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal("io.ReadAll() error =", err)
+			}
+			if err := rc.Close(); err != nil {
+				t.Error("Close() error =", err)
+			}
+			if !bytes.Equal(got, blob) {
+				t.Error("fetch() got mismatched content")
+			}
+			slices.Sort(*ranges)
+			if !slices.Equal(*ranges, tt.wantRanges) {
+				t.Errorf("Range = %q, want %q", *ranges, tt.wantRanges)
+			}
+		})
+	}
+}
+
+func TestFetch_segmentsBelowThreshold(t *testing.T) {
+	blob := []byte("hello world")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	repo, ranges := newTestRepository(t, blob, true)
+	rc, err := fetch(context.Background(), repo, desc, filepath.Join(t.TempDir(), "blob.partial"), SegmentOptions{
+		Repository: repo,
+		Threshold:  1024,
+		Count:      4,
+	})
+	if err != nil {
+		t.Fatal("fetch() error =", err)
+	}
+	defer rc.Close()
+	if got, err := io.ReadAll(rc); err != nil || !bytes.Equal(got, blob) {
+		t.Errorf("fetch() = %q, %v, want %q", got, err, blob)
+	}
+	if want := []string{""}; !slices.Equal(*ranges, want) {
+		t.Errorf("Range = %q, want %q", *ranges, want)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras/internal/descriptor"
+)
+
+// target is a target resuming interrupted downloads of named blobs.
+type target struct {
+	oras.ReadOnlyTarget
+	dir     string
+	segOpts SegmentOptions
+}
+
+// NewTarget wraps source so that the named blobs are downloaded resumably,
+// with the partial files kept in dir.
+func NewTarget(source oras.ReadOnlyTarget, dir string) oras.ReadOnlyTarget {
+	return NewTargetWithSegments(source, dir, SegmentOptions{})
+}
+
+// NewTargetWithSegments wraps source like NewTarget, where large named blobs
+// are downloaded in segments according to segOpts. Segments are fetched from
+// segOpts.Repository directly, bypassing source.
+func NewTargetWithSegments(source oras.ReadOnlyTarget, dir string, segOpts SegmentOptions) oras.ReadOnlyTarget {
+	return &target{
+		ReadOnlyTarget: source,
+		dir:            dir,
+		segOpts:        segOpts,
+	}
+}
+
+// Fetch fetches the content identified by the descriptor.
+func (t *target) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	if target.Annotations[ocispec.AnnotationTitle] == "" || descriptor.IsManifest(target) {
+		return t.ReadOnlyTarget.Fetch(ctx, target)
+	}
+	return fetch(ctx, t.ReadOnlyTarget, target, filepath.Join(t.dir, partialName(target)), t.segOpts)
+}
+
+// FetchReference fetches the content identified by the reference.
+func (t *target) FetchReference(ctx context.Context, reference string) (ocispec.Descriptor, io.ReadCloser, error) {
+	if refFetcher, ok := t.ReadOnlyTarget.(registry.ReferenceFetcher); ok {
+		return refFetcher.FetchReference(ctx, reference)
+	}
+	desc, err := t.Resolve(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	rc, err := t.Fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	return desc, rc, nil
+}
+
+// partialName returns the name of the file keeping the partial download of
+// desc.
+func partialName(desc ocispec.Descriptor) string {
+	return "." + desc.Digest.Encoded() + partialSuffix
+}
+
+// partialSuffix is the suffix of the files keeping partial downloads.
+const partialSuffix = ".partial"
+
+// IsPartialName returns true if name is the name of a file keeping a partial
+// download or its progress.
+func IsPartialName(name string) bool {
+	return strings.HasPrefix(name, ".") && (strings.HasSuffix(name, partialSuffix) || strings.HasSuffix(name, partialSuffix+sidecarSuffix))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestTarget_Fetch(t *testing.T) {
+	blob := []byte("hello world")
+	unnamed := content.NewDescriptorFromBytes("test", blob)
+	named := unnamed
+	named.Annotations = map[string]string{ocispec.AnnotationTitle: "hello.txt"}
+	store := memory.New()
+	ctx := context.Background()
+	if err := store.Push(ctx, unnamed, bytes.NewReader(blob)); err != nil {
+		t.Fatal("Store.Push() error =", err)
+	}
+	dir := t.TempDir()
+	target := NewTarget(store, dir)
+
+	for _, desc := range []ocispec.Descriptor{unnamed, named} {
+		rc, err := target.Fetch(ctx, desc)
+		if err != nil {
+			t.Fatal("Target.Fetch() error =", err)
+		}
+		got, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal("io.ReadAll() error =", err)
+		}
+		rc.Close()
+		if !bytes.Equal(got, blob) {
+			t.Errorf("Target.Fetch() = %q, want %q", got, blob)
+		}
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("os.ReadDir() = %v, %v, want no partial files", entries, err)
+	}
+
+	// interrupted download of the named blob is kept in dir
+	rc, err := target.Fetch(ctx, named)
+	if err != nil {
+		t.Fatal("Target.Fetch() error =", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		t.Fatal("io.ReadFull() error =", err)
+	}
+	rc.Close()
+	partial := filepath.Join(dir, partialName(named))
+	if _, err := os.Stat(partial + sidecarSuffix); err != nil {
+		t.Errorf("progress of the interrupted download is not recorded: %v", err)
+	}
+}
+
+func TestIsPartialName(t *testing.T) {
+	desc := content.NewDescriptorFromBytes("test", []byte("hello"))
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: partialName(desc), want: true},
+		{name: partialName(desc) + sidecarSuffix, want: true},
+		{name: "hello.partial", want: false},
+		{name: ".hello.txt", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsPartialName(tt.name); got != tt.want {
+			t.Errorf("IsPartialName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
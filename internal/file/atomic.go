@@ -0,0 +1,235 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// NewStagingDir creates a hidden staging directory beside dir, so that it can
+// replace dir by renaming on the same file system. The staging directory has
+// the same permissions as dir if dir exists.
+func NewStagingDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0777); err != nil {
+		return "", err
+	}
+	staging, err := os.MkdirTemp(parent, "."+filepath.Base(dir)+".staging-*")
+	if err != nil {
+		return "", err
+	}
+	// os.MkdirTemp creates the directory with 0700
+	var chmodErr error
+	if fi, err := os.Stat(dir); err == nil {
+		chmodErr = os.Chmod(staging, fi.Mode().Perm())
+	} else if errors.Is(err, fs.ErrNotExist) {
+		// apply the umask as os.MkdirAll does
+		if err := os.Remove(staging); err != nil {
+			return "", err
+		}
+		chmodErr = os.Mkdir(staging, 0777)
+	} else {
+		chmodErr = err
+	}
+	if chmodErr != nil {
+		os.RemoveAll(staging)
+		return "", chmodErr
+	}
+	return staging, nil
+}
+
+// ReplaceDir replaces dir with the staging directory by renaming. The
+// original dir, if any, is renamed aside first and removed once the staging
+// directory is in place, or renamed back if the replacement fails.
+func ReplaceDir(staging string, dir string) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return os.Rename(staging, dir)
+		}
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("failed to replace %s: not a directory", dir)
+	}
+
+	// reserve a unique name to rename the original directory aside
+	old, err := os.MkdirTemp(filepath.Dir(staging), "."+filepath.Base(dir)+".old-*")
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(old); err != nil {
+		return err
+	}
+	if err := os.Rename(dir, old); err != nil {
+		return err
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		if restoreErr := os.Rename(old, dir); restoreErr != nil {
+			return fmt.Errorf("failed to replace %s: %w, and failed to restore it from %s: %v", dir, err, old, restoreErr)
+		}
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// LinkOrCopy creates dst as a hard link to the file src, or as a copy of it
+// if hard links are not supported. The parent directories of dst are created
+// if missing.
+func LinkOrCopy(src string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// UnshareFile replaces the file at path with a copy of it if it is a hard link
+// to the file orig, so that its metadata can be changed without changing orig.
+// The copy keeps the permissions and modification time of the file.
+func UnshareFile(path string, orig string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	origInfo, err := os.Lstat(orig)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !fi.Mode().IsRegular() || !os.SameFile(fi, origInfo) {
+		return nil
+	}
+
+	in, err := os.Open(orig)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".copy-*")
+	if err != nil {
+		return err
+	}
+	tmp := out.Name()
+	if _, err = io.Copy(out, in); err == nil {
+		err = out.Chmod(fi.Mode().Perm())
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chtimes(tmp, fi.ModTime(), fi.ModTime())
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// LinkTree recreates the files and directories under src missing in dst, with
+// the regular files hard linked or copied by LinkOrCopy. skip is called with
+// the slash-separated paths relative to src, and the directories skipped are
+// not walked into. It is a no-op if src does not exist.
+func LinkTree(src string, dst string, skip func(name string) bool) error {
+	if _, err := os.Stat(src); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == src {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if skip != nil && skip(filepath.ToSlash(rel)) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+		if fi, err := os.Lstat(target); err == nil {
+			if d.IsDir() && !fi.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.Mkdir(target, fi.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return LinkOrCopy(path, target)
+		default:
+			// skip sockets, devices and other special files
+			return nil
+		}
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+}
+
+func TestReplaceDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "out")
+	for _, content := range []string{"v1", "v2"} {
+		staging, err := NewStagingDir(dir)
+		if err != nil {
+			t.Fatal("NewStagingDir() error =", err)
+		}
+		if got := filepath.Dir(staging); got != parent {
+			t.Fatalf("NewStagingDir() = %s, want a directory in %s", staging, parent)
+		}
+		writeTestFiles(t, staging, map[string]string{"foo": content})
+		if err := ReplaceDir(staging, dir); err != nil {
+			t.Fatal("ReplaceDir() error =", err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, "foo"))
+		if err != nil {
+			t.Fatal("os.ReadFile() error =", err)
+		}
+		if string(got) != content {
+			t.Errorf("ReplaceDir() got content %q, want %q", got, content)
+		}
+	}
+	// only the output directory is left
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatal("os.ReadDir() error =", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "out" {
+		t.Errorf("ReplaceDir() left %v in %s", entries, parent)
+	}
+}
+
+func TestReplaceDir_notDirectory(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "out")
+	writeTestFiles(t, parent, map[string]string{"out": "file"})
+	staging, err := NewStagingDir(dir)
+	if err != nil {
+		t.Fatal("NewStagingDir() error =", err)
+	}
+	if err := ReplaceDir(staging, dir); err == nil {
+		t.Error("ReplaceDir() error = nil, want error")
+	}
+}
+
+func TestLinkTree(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"kept":         "old",
+		"pulled":       "old",
+		"sub/kept":     "old",
+		"skipped/file": "old",
+	})
+	if err := os.Symlink("kept", filepath.Join(src, "link")); err != nil {
+		t.Fatal("os.Symlink() error =", err)
+	}
+	writeTestFiles(t, dst, map[string]string{"pulled": "new"})
+
+	if err := LinkTree(src, dst, func(name string) bool {
+		return name == "skipped"
+	}); err != nil {
+		t.Fatal("LinkTree() error =", err)
+	}
+	for name, want := range map[string]string{
+		"kept":     "old",
+		"pulled":   "new",
+		"sub/kept": "old",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal("os.ReadFile() error =", err)
+		}
+		if string(got) != want {
+			t.Errorf("LinkTree() got content %q of %s, want %q", got, name, want)
+		}
+	}
+	if got, err := os.Readlink(filepath.Join(dst, "link")); err != nil || got != "kept" {
+		t.Errorf("LinkTree() got link %q, error = %v, want %q", got, err, "kept")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "skipped")); !os.IsNotExist(err) {
+		t.Errorf("LinkTree() did not skip %s, error = %v", "skipped", err)
+	}
+
+	// missing source is ignored
+	if err := LinkTree(filepath.Join(src, "missing"), dst, nil); err != nil {
+		t.Error("LinkTree() error =", err)
+	}
+}
+
+func TestUnshareFile(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"orig": "foo", "other": "bar"})
+	orig := filepath.Join(root, "orig")
+	linked := filepath.Join(root, "linked")
+	if err := os.Link(orig, linked); err != nil {
+		t.Skip("hard links are not supported:", err)
+	}
+	if err := UnshareFile(linked, orig); err != nil {
+		t.Fatal("UnshareFile() error =", err)
+	}
+	if err := os.Chmod(linked, 0600); err != nil {
+		t.Fatal("os.Chmod() error =", err)
+	}
+	fi, err := os.Stat(orig)
+	if err != nil {
+		t.Fatal("os.Stat() error =", err)
+	}
+	if got := fi.Mode().Perm(); got != 0644 {
+		t.Errorf("UnshareFile() changed the mode of the original file to %v", got)
+	}
+	got, err := os.ReadFile(linked)
+	if err != nil {
+		t.Fatal("os.ReadFile() error =", err)
+	}
+	if string(got) != "foo" {
+		t.Errorf("UnshareFile() got content %q, want %q", got, "foo")
+	}
+
+	// files not linked to orig are left as is
+	other := filepath.Join(root, "other")
+	before, err := os.Stat(other)
+	if err != nil {
+		t.Fatal("os.Stat() error =", err)
+	}
+	if err := UnshareFile(other, orig); err != nil {
+		t.Fatal("UnshareFile() error =", err)
+	}
+	after, err := os.Stat(other)
+	if err != nil {
+		t.Fatal("os.Stat() error =", err)
+	}
+	if !os.SameFile(before, after) {
+		t.Error("UnshareFile() replaced a file not linked to the original file")
+	}
+}
@@ -47,9 +47,10 @@ func PrepareManifestContent(path string) ([]byte, error) {
 }
 
 // PrepareBlobContent prepares the content descriptor for blob from the file
-// path or stdin. Use the input digest and size if they are provided. Will
-// return error if the content is from stdin but the content digest and size
-// are missing.
+// path or stdin. Use the input digest and size if they are provided. If the
+// content is from stdin but the content digest or size is missing, the
+// content is spooled to a temporary file, which is removed on closing the
+// returned reader.
 func PrepareBlobContent(path string, mediaType string, dgstStr string, size int64) (desc ocispec.Descriptor, rc io.ReadCloser, prepareErr error) {
 	if path == "" {
 		return ocispec.Descriptor{}, nil, errors.New("missing file name")
@@ -67,23 +68,21 @@ func PrepareBlobContent(path string, mediaType string, dgstStr string, size int6
 
 	// prepares the content descriptor from stdin
 	if path == "-" {
-		// throw err if size or digest is not provided.
-		if size < 0 {
-			return ocispec.Descriptor{}, nil, errors.New("content size must be provided if it is read from stdin")
-		}
-		if dgst == "" {
-			return ocispec.Descriptor{}, nil, errors.New("content digest must be provided if it is read from stdin")
-		}
-		return ocispec.Descriptor{
+		desc := ocispec.Descriptor{
 			MediaType: mediaType,
 			Digest:    dgst,
 			Size:      size,
-		}, os.Stdin, nil
+		}
+		if size < 0 || dgst == "" {
+			// spool the content to know its size and digest
+			return spoolBlobContent(os.Stdin, desc)
+		}
+		return desc, os.Stdin, nil
 	}
 
-	file, err := os.Open(path)
+	desc, file, err := OpenBlobContent(path, mediaType, size)
 	if err != nil {
-		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to open %s: %w", path, err)
+		return ocispec.Descriptor{}, nil, err
 	}
 	defer func() {
 		if prepareErr != nil {
@@ -91,15 +90,6 @@ func PrepareBlobContent(path string, mediaType string, dgstStr string, size int6
 		}
 	}()
 
-	fi, err := file.Stat()
-	if err != nil {
-		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to stat %s: %w", path, err)
-	}
-	actualSize := fi.Size()
-	if size >= 0 && size != actualSize {
-		return ocispec.Descriptor{}, nil, fmt.Errorf("input size %d does not match the actual content size %d", size, actualSize)
-	}
-
 	if dgst == "" {
 		dgst, err = digest.FromReader(file)
 		if err != nil {
@@ -109,10 +99,90 @@ func PrepareBlobContent(path string, mediaType string, dgstStr string, size int6
 			return ocispec.Descriptor{}, nil, err
 		}
 	}
+	desc.Digest = dgst
+	return desc, file, nil
+}
+
+// OpenBlobContent opens the file at path for reading blob content, and
+// returns the content descriptor without the digest, which is left for the
+// caller to compute while reading the content. Will return error if size is
+// provided but does not match the actual content size.
+func OpenBlobContent(path string, mediaType string, size int64) (desc ocispec.Descriptor, file *os.File, openErr error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		if openErr != nil {
+			file.Close()
+		}
+	}()
 
+	fi, err := file.Stat()
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	actualSize := fi.Size()
+	if size >= 0 && size != actualSize {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("input size %d does not match the actual content size %d", size, actualSize)
+	}
 	return ocispec.Descriptor{
 		MediaType: mediaType,
-		Digest:    dgst,
 		Size:      actualSize,
 	}, file, nil
 }
+
+// spoolBlobContent copies the content from r to a temporary file, and returns
+// the descriptor with the computed size and digest, which are verified against
+// expected if provided.
+func spoolBlobContent(r io.Reader, expected ocispec.Descriptor) (desc ocispec.Descriptor, rc io.ReadCloser, spoolErr error) {
+	fp, err := os.CreateTemp("", "oras_blob_*")
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmp := &tempFile{File: fp}
+	defer func() {
+		if spoolErr != nil {
+			tmp.Close()
+		}
+	}()
+
+	alg := digest.Canonical
+	if expected.Digest != "" {
+		alg = expected.Digest.Algorithm()
+	}
+	digester := alg.Digester()
+	size, err := io.Copy(io.MultiWriter(fp, digester.Hash()), r)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to spool content: %w", err)
+	}
+	if expected.Size >= 0 && expected.Size != size {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("input size %d does not match the actual content size %d", expected.Size, size)
+	}
+	dgst := digester.Digest()
+	if expected.Digest != "" && expected.Digest != dgst {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("input digest %s does not match the actual content digest %s", expected.Digest, dgst)
+	}
+	if _, err := fp.Seek(0, io.SeekStart); err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	return ocispec.Descriptor{
+		MediaType: expected.MediaType,
+		Digest:    dgst,
+		Size:      size,
+	}, tmp, nil
+}
+
+// tempFile is a temporary file removed on close.
+type tempFile struct {
+	*os.File
+}
+
+// Close closes and removes the file.
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
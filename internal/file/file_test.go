@@ -16,6 +16,7 @@ limitations under the License.
 package file_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -225,19 +226,46 @@ func TestFile_PrepareBlobContent_fromStdin(t *testing.T) {
 		t.Errorf("PrepareBlobContent() = %v, want %v", gotRc, tmpfile)
 	}
 
-	// test PrepareBlobContent from stdin with missing size
-	_, _, err = file.PrepareBlobContent("-", blobMediaType, "", -1)
-	expected := "content size must be provided if it is read from stdin"
-	if err.Error() != expected {
-		t.Fatalf("PrepareBlobContent() error = %v, wantErr %v", err, expected)
+	// test PrepareBlobContent from stdin with missing size and digest
+	if _, err = tmpfile.Seek(0, io.SeekStart); err != nil {
+		t.Fatal("error calling Seek(), error =", err)
+	}
+	gotDesc, gotRc, err = file.PrepareBlobContent("-", blobMediaType, "", -1)
+	if err != nil {
+		t.Fatal("PrepareBlobContent() error=", err)
+	}
+	if !reflect.DeepEqual(gotDesc, wantDesc) {
+		t.Errorf("PrepareBlobContent() = %v, want %v", gotDesc, wantDesc)
+	}
+	actualContent, err := io.ReadAll(gotRc)
+	if err != nil {
+		t.Fatal("PrepareBlobContent(): not able to read content from rc, error=", err)
+	}
+	if !bytes.Equal(actualContent, content) {
+		t.Errorf("PrepareBlobContent() = %v, want %v", actualContent, content)
+	}
+	if err := gotRc.Close(); err != nil {
+		t.Fatal("error calling rc.Close(), error =", err)
 	}
 
-	// test PrepareBlobContent from stdin with missing digest
+	// test PrepareBlobContent from stdin with mismatched size
+	if _, err = tmpfile.Seek(0, io.SeekStart); err != nil {
+		t.Fatal("error calling Seek(), error =", err)
+	}
 	_, _, err = file.PrepareBlobContent("-", blobMediaType, "", 5)
-	expected = "content digest must be provided if it is read from stdin"
-	if err.Error() != expected {
+	expected := "input size 5 does not match the actual content size 12"
+	if err == nil || err.Error() != expected {
 		t.Fatalf("PrepareBlobContent() error = %v, wantErr %v", err, expected)
 	}
+
+	// test PrepareBlobContent from stdin with mismatched digest
+	if _, err = tmpfile.Seek(0, io.SeekStart); err != nil {
+		t.Fatal("error calling Seek(), error =", err)
+	}
+	_, _, err = file.PrepareBlobContent("-", blobMediaType, digest.FromString("foo").String(), -1)
+	if err == nil {
+		t.Fatal("PrepareBlobContent() error = nil, want error")
+	}
 }
 
 func TestFile_PrepareBlobContent_errDigestInvalidFormat(t *testing.T) {
@@ -266,3 +294,32 @@ func TestFile_PrepareBlobContent_errOpenFile(t *testing.T) {
 		t.Fatalf("PrepareBlobContent() error = %v, wantErr %v", err, expected)
 	}
 }
+
+func TestFile_OpenBlobContent(t *testing.T) {
+	content := []byte("hello world!")
+	path := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(path, content, 0444); err != nil {
+		t.Fatal("error calling WriteFile(), error =", err)
+	}
+
+	want := ocispec.Descriptor{
+		MediaType: blobMediaType,
+		Size:      int64(len(content)),
+	}
+	got, fp, err := file.OpenBlobContent(path, blobMediaType, -1)
+	if err != nil {
+		t.Fatal("OpenBlobContent() error=", err)
+	}
+	defer fp.Close()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OpenBlobContent() = %v, want %v", got, want)
+	}
+	if actualContent, err := io.ReadAll(fp); err != nil || !bytes.Equal(actualContent, content) {
+		t.Errorf("OpenBlobContent() content = %q, %v, want %q", actualContent, err, content)
+	}
+
+	// test OpenBlobContent with mismatched size
+	if _, _, err := file.OpenBlobContent(path, blobMediaType, 15); err == nil {
+		t.Error("OpenBlobContent() error = nil, want error")
+	}
+}
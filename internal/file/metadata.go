@@ -0,0 +1,182 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"oras.land/oras-go/v2/content/file"
+)
+
+// Annotations recording the metadata of files.
+const (
+	// AnnotationMode is the annotation key for the permission bits of a file
+	// in octal, e.g. 0755.
+	AnnotationMode = "land.oras.file.mode"
+	// AnnotationModTime is the annotation key for the modification time of a
+	// file in RFC 3339 format.
+	AnnotationModTime = "land.oras.file.mtime"
+	// AnnotationSymlink is the annotation key for the target of a symbolic
+	// link.
+	AnnotationSymlink = "land.oras.file.symlink"
+)
+
+// MetadataOptions configures how the metadata of files is recorded.
+type MetadataOptions struct {
+	// Reproducible normalizes the permission bits to 0755 for executable
+	// files and 0644 for others, and records ModTime as the modification
+	// time.
+	Reproducible bool
+	// ModTime is the modification time recorded in reproducible mode.
+	ModTime time.Time
+}
+
+// MetadataAnnotations returns the annotations recording the permission bits,
+// the modification time and, if path is a symbolic link, the link target of
+// the file at path.
+func MetadataAnnotations(path string, opts MetadataOptions) (map[string]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	mode := fi.Mode().Perm()
+	modTime := fi.ModTime()
+	if opts.Reproducible {
+		mode = 0644
+		if fi.Mode()&0111 != 0 {
+			mode = 0755
+		}
+		modTime = opts.ModTime
+		if modTime.IsZero() {
+			modTime = time.Unix(0, 0)
+		}
+	}
+	annotations := map[string]string{
+		AnnotationMode:    fmt.Sprintf("%04o", mode),
+		AnnotationModTime: modTime.UTC().Format(time.RFC3339Nano),
+	}
+
+	lfi, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if lfi.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil, err
+		}
+		annotations[AnnotationSymlink] = filepath.ToSlash(target)
+	}
+	return annotations, nil
+}
+
+// RestoreMetadata restores the metadata recorded in annotations to the file
+// pulled to path. A file recorded as a symbolic link is replaced with the
+// link, whose target must stay within root unless allowPathTraversal is set.
+// The permission bits and the modification time are not applied to symbolic
+// links.
+func RestoreMetadata(root string, path string, annotations map[string]string, allowPathTraversal bool) error {
+	if target, ok := annotations[AnnotationSymlink]; ok {
+		return restoreSymlink(root, path, filepath.FromSlash(target), allowPathTraversal)
+	}
+	if value, ok := annotations[AnnotationMode]; ok {
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || mode > 0777 {
+			return fmt.Errorf("invalid file mode %q of %s", value, path)
+		}
+		if err := os.Chmod(path, fs.FileMode(mode)); err != nil {
+			return err
+		}
+	}
+	if value, ok := annotations[AnnotationModTime]; ok {
+		modTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("invalid modification time %q of %s: %w", value, path, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreSymlink replaces the file at path with a symbolic link to target.
+func restoreSymlink(root string, path string, target string, allowPathTraversal bool) error {
+	if target == "" {
+		return fmt.Errorf("invalid empty symbolic link target of %s", path)
+	}
+	if !allowPathTraversal {
+		if filepath.IsAbs(target) {
+			return fmt.Errorf("%s: symbolic link to %s: %w", path, target, file.ErrPathTraversalDisallowed)
+		}
+		absRoot, err := evalPath(root, "")
+		if err != nil {
+			return err
+		}
+		// follow the symbolic links restored earlier, so that chained links
+		// cannot point out of root
+		absTarget, err := evalPath(filepath.Dir(path), target)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(absRoot, absTarget)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%s: symbolic link to %s: %w", path, target, file.ErrPathTraversalDisallowed)
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return os.Symlink(target, path)
+}
+
+// evalPath returns the absolute path which the relative path rel resolves to
+// from dir, following the existing symbolic links in dir and along rel. The
+// elements of rel not existing yet are joined lexically.
+func evalPath(dir string, rel string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	current, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(rel), "/") {
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+			continue
+		}
+		next := filepath.Join(current, elem)
+		if current, err = filepath.EvalSymlinks(next); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+			current = next
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"oras.land/oras-go/v2/content/file"
+)
+
+func TestMetadataAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.sh")
+	if err := os.WriteFile(path, []byte("echo hi"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	if err := os.Chmod(path, 0750); err != nil {
+		t.Fatal("os.Chmod() error =", err)
+	}
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal("os.Chtimes() error =", err)
+	}
+	link := filepath.Join(dir, "link.sh")
+	if err := os.Symlink("run.sh", link); err != nil {
+		t.Fatal("os.Symlink() error =", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		opts MetadataOptions
+		want map[string]string
+	}{
+		{
+			name: "regular file",
+			path: path,
+			want: map[string]string{
+				AnnotationMode:    "0750",
+				AnnotationModTime: "2020-01-02T03:04:05.000000006Z",
+			},
+		},
+		{
+			name: "symbolic link",
+			path: link,
+			want: map[string]string{
+				AnnotationMode:    "0750",
+				AnnotationModTime: "2020-01-02T03:04:05.000000006Z",
+				AnnotationSymlink: "run.sh",
+			},
+		},
+		{
+			name: "reproducible",
+			path: path,
+			opts: MetadataOptions{Reproducible: true},
+			want: map[string]string{
+				AnnotationMode:    "0755",
+				AnnotationModTime: "1970-01-01T00:00:00Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MetadataAnnotations(tt.path, tt.opts)
+			if err != nil {
+				t.Fatal("MetadataAnnotations() error =", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MetadataAnnotations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreMetadata(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "run.sh")
+	if err := os.WriteFile(path, []byte("echo hi"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	if err := RestoreMetadata(root, path, map[string]string{
+		AnnotationMode:    "0750",
+		AnnotationModTime: "2020-01-02T03:04:05Z",
+	}, false); err != nil {
+		t.Fatal("RestoreMetadata() error =", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal("os.Stat() error =", err)
+	}
+	if got := fi.Mode().Perm(); got != 0750 {
+		t.Errorf("RestoreMetadata() mode = %v, want %v", got, os.FileMode(0750))
+	}
+	if want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC); !fi.ModTime().Equal(want) {
+		t.Errorf("RestoreMetadata() modification time = %v, want %v", fi.ModTime(), want)
+	}
+
+	link := filepath.Join(root, "sub", "link.sh")
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		t.Fatal("os.MkdirAll() error =", err)
+	}
+	if err := os.WriteFile(link, []byte("echo hi"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	if err := RestoreMetadata(root, link, map[string]string{AnnotationSymlink: "../run.sh"}, false); err != nil {
+		t.Fatal("RestoreMetadata() error =", err)
+	}
+	if target, err := os.Readlink(link); err != nil || target != filepath.FromSlash("../run.sh") {
+		t.Errorf("os.Readlink() = %v, %v, want %v", target, err, "../run.sh")
+	}
+}
+
+func TestRestoreMetadata_invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     error
+	}{
+		{name: "invalid mode", annotations: map[string]string{AnnotationMode: "0999"}},
+		{name: "mode out of range", annotations: map[string]string{AnnotationMode: "4755"}},
+		{name: "invalid modification time", annotations: map[string]string{AnnotationModTime: "yesterday"}},
+		{name: "link out of root", annotations: map[string]string{AnnotationSymlink: "../secret"}, wantErr: file.ErrPathTraversalDisallowed},
+		{name: "absolute link", annotations: map[string]string{AnnotationSymlink: "/etc/passwd"}, wantErr: file.ErrPathTraversalDisallowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, "foo")
+			if err := os.WriteFile(path, []byte("foo"), 0644); err != nil {
+				t.Fatal("os.WriteFile() error =", err)
+			}
+			err := RestoreMetadata(root, path, tt.annotations, false)
+			if err == nil {
+				t.Fatal("RestoreMetadata() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("RestoreMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestoreMetadata_chainedLinks(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "root")
+	for _, name := range []string{"d", "e", "f"} {
+		writeTestFiles(t, root, map[string]string{name: name})
+	}
+	// d points to root itself, which is allowed
+	if err := RestoreMetadata(root, filepath.Join(root, "d"), map[string]string{AnnotationSymlink: "."}, false); err != nil {
+		t.Fatal("RestoreMetadata() error =", err)
+	}
+	// e is lexically within root but points out of root through d
+	err := RestoreMetadata(root, filepath.Join(root, "e"), map[string]string{AnnotationSymlink: "d/.."}, false)
+	if !errors.Is(err, file.ErrPathTraversalDisallowed) {
+		t.Fatalf("RestoreMetadata() error = %v, want %v", err, file.ErrPathTraversalDisallowed)
+	}
+	// f points into root through d
+	if err := RestoreMetadata(root, filepath.Join(root, "f"), map[string]string{AnnotationSymlink: "d/d/e"}, false); err != nil {
+		t.Fatal("RestoreMetadata() error =", err)
+	}
+	if err := RestoreMetadata(root, filepath.Join(root, "e"), map[string]string{AnnotationSymlink: "d/.."}, true); err != nil {
+		t.Fatal("RestoreMetadata() with path traversal allowed error =", err)
+	}
+}
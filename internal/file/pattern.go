@@ -0,0 +1,198 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// IgnoreFileName is the name of the file listing the patterns of files to be
+// excluded, which is looked up in the current working directory.
+const IgnoreFileName = ".orasignore"
+
+// IsPattern returns true if path contains any glob meta characters.
+func IsPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
+
+// Glob returns the names of the regular files matching pattern in lexical
+// order. The pattern supports ** to match any number of directories.
+func Glob(pattern string) ([]string, error) {
+	if !doublestar.ValidatePathPattern(pattern) {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+	matches, err := doublestar.FilepathGlob(pattern, doublestar.WithFilesOnly(), doublestar.WithFailOnIOErrors())
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand pattern %q: %w", pattern, err)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// ReadFileList reads the list of entries separated by newlines or NUL
+// characters from r. Empty entries are skipped.
+func ReadFileList(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var entries []string
+	if bytes.IndexByte(data, 0) >= 0 {
+		for _, entry := range bytes.Split(data, []byte{0}) {
+			if len(entry) > 0 {
+				entries = append(entries, string(entry))
+			}
+		}
+		return entries, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if entry := strings.TrimRight(scanner.Text(), "\r"); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// ReadIgnoreFile reads the exclusion patterns from the ignore file at path.
+// Empty lines and lines starting with # are skipped. A missing ignore file
+// yields no patterns.
+func ReadIgnoreFile(path string) ([]string, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer fp.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return patterns, nil
+}
+
+// excludeRule is a parsed exclusion pattern.
+type excludeRule struct {
+	pattern string
+	negated bool
+}
+
+// Excluder matches file paths against exclusion patterns. The patterns are
+// evaluated in order and the last matching pattern wins. A pattern prefixed
+// with ! re-includes the files excluded by previous patterns. A path is also
+// matched if any of its parent directories matches.
+type Excluder struct {
+	rules []excludeRule
+}
+
+// NewExcluder creates an excluder with the given patterns.
+func NewExcluder(patterns []string) (*Excluder, error) {
+	rules := make([]excludeRule, 0, len(patterns))
+	for _, p := range patterns {
+		var rule excludeRule
+		if rule.pattern, rule.negated = strings.CutPrefix(p, "!"); rule.negated && rule.pattern == "" {
+			return nil, fmt.Errorf("invalid exclusion pattern %q", p)
+		}
+		rule.pattern = cleanPath(rule.pattern)
+		if !doublestar.ValidatePattern(rule.pattern) {
+			return nil, fmt.Errorf("invalid exclusion pattern %q", p)
+		}
+		rules = append(rules, rule)
+	}
+	return &Excluder{rules: rules}, nil
+}
+
+// Excluded returns true if name is excluded.
+func (e *Excluder) Excluded(name string) bool {
+	name = cleanPath(name)
+	excluded := false
+	for _, rule := range e.rules {
+		if match(rule.pattern, name) {
+			excluded = !rule.negated
+		}
+	}
+	return excluded
+}
+
+// Matcher matches file paths against glob patterns. A path is matched if it
+// or any of its parent directories matches any of the patterns.
+type Matcher struct {
+	patterns []string
+}
+
+// NewMatcher creates a matcher with the given patterns.
+func NewMatcher(patterns []string) (*Matcher, error) {
+	cleaned := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		pattern := cleanPath(p)
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid pattern %q", p)
+		}
+		cleaned = append(cleaned, pattern)
+	}
+	return &Matcher{patterns: cleaned}, nil
+}
+
+// Match returns true if name is matched.
+func (m *Matcher) Match(name string) bool {
+	name = cleanPath(name)
+	for _, pattern := range m.patterns {
+		if match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if name or any of its parent directories matches
+// pattern.
+func match(pattern, name string) bool {
+	for ; name != "." && name != "/"; name = path.Dir(name) {
+		if doublestar.MatchUnvalidated(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanPath converts p to a slash-separated clean path without the leading
+// "./".
+func cleanPath(p string) string {
+	return path.Clean(filepath.ToSlash(p))
+}
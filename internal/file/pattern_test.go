@@ -0,0 +1,162 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.onnx", "sub/b.onnx", "sub/deep/c.onnx", "sub/d.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+
+	got, err := Glob(filepath.Join(dir, "**", "*.onnx"))
+	if err != nil {
+		t.Fatal("Glob() error =", err)
+	}
+	want := []string{
+		filepath.Join(dir, "a.onnx"),
+		filepath.Join(dir, "sub", "b.onnx"),
+		filepath.Join(dir, "sub", "deep", "c.onnx"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Glob() = %v, want %v", got, want)
+	}
+
+	// directories are not matched
+	got, err = Glob(filepath.Join(dir, "s*"))
+	if err != nil {
+		t.Fatal("Glob() error =", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Glob() = %v, want no matches", got)
+	}
+
+	if _, err := Glob("[invalid"); err == nil {
+		t.Error("Glob() error = nil, want error")
+	}
+}
+
+func TestReadFileList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "newline separated", input: "a.txt\nb.txt:text/plain\r\n\nc d.txt\n", want: []string{"a.txt", "b.txt:text/plain", "c d.txt"}},
+		{name: "NUL separated", input: "a.txt\x00with\nnewline\x00\x00", want: []string{"a.txt", "with\nnewline"}},
+		{name: "empty", input: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFileList(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal("ReadFileList() error =", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadFileList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), IgnoreFileName)
+	if patterns, err := ReadIgnoreFile(path); err != nil || patterns != nil {
+		t.Fatalf("ReadIgnoreFile() = %v, %v, want no patterns for missing file", patterns, err)
+	}
+	if err := os.WriteFile(path, []byte("# comment\n\n  tmp  \n!tmp/keep\n"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	got, err := ReadIgnoreFile(path)
+	if err != nil {
+		t.Fatal("ReadIgnoreFile() error =", err)
+	}
+	if want := []string{"tmp", "!tmp/keep"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadIgnoreFile() = %v, want %v", got, want)
+	}
+}
+
+func TestExcluder_Excluded(t *testing.T) {
+	excluder, err := NewExcluder([]string{"models/tmp", "**/*.log", "!models/tmp/keep.onnx", "./cache/**"})
+	if err != nil {
+		t.Fatal("NewExcluder() error =", err)
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"models/a.onnx", false},
+		{"models/tmp", true},
+		{"models/tmp/b.onnx", true},
+		{"./models/tmp/deep/c.onnx", true},
+		{"models/tmp/keep.onnx", false},
+		{"debug.log", true},
+		{"models/run.log", true},
+		{"cache/blob", true},
+		{"cached", false},
+	}
+	for _, tt := range tests {
+		if got := excluder.Excluded(tt.name); got != tt.want {
+			t.Errorf("Excluder.Excluded(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, pattern := range []string{"!", "[invalid"} {
+		if _, err := NewExcluder([]string{pattern}); err == nil {
+			t.Errorf("NewExcluder(%q) error = nil, want error", pattern)
+		}
+	}
+}
+
+func TestMatcher_Match(t *testing.T) {
+	matcher, err := NewMatcher([]string{"models/**/*.onnx", "docs"})
+	if err != nil {
+		t.Fatal("NewMatcher() error =", err)
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"models/a.onnx", true},
+		{"./models/sub/b.onnx", true},
+		{"models/a.txt", false},
+		{"docs", true},
+		{"docs/readme.md", true},
+		{"readme.md", false},
+	}
+	for _, tt := range tests {
+		if got := matcher.Match(tt.name); got != tt.want {
+			t.Errorf("Matcher.Match(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := NewMatcher([]string{"[invalid"}); err == nil {
+		t.Error("NewMatcher() error = nil, want error")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Matches returns true if the file at path exists with the size and the
+// digest described by desc.
+func Matches(path string, desc ocispec.Descriptor) (bool, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !fi.Mode().IsRegular() || fi.Size() != desc.Size || !desc.Digest.Algorithm().Available() {
+		return false, nil
+	}
+	fp, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer fp.Close()
+	dgst, err := desc.Digest.Algorithm().FromReader(fp)
+	if err != nil {
+		return false, err
+	}
+	return dgst == desc.Digest, nil
+}
+
+// Prune removes the files under root not kept by keep, as well as the
+// directories left empty. keep is called with the slash-separated paths
+// relative to root, and the directories kept are not walked into. Returns the
+// paths of the removed files.
+func Prune(root string, keep func(name string) bool) ([]string, error) {
+	var removed, dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if keep(filepath.ToSlash(rel)) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed = append(removed, path)
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+	// remove the deepest directories first
+	slices.Reverse(dirs)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return removed, err
+		}
+		if len(entries) == 0 {
+			if err := os.Remove(dir); err != nil {
+				return removed, err
+			}
+		}
+	}
+	return removed, nil
+}
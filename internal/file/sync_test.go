@@ -0,0 +1,92 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"oras.land/oras-go/v2/content"
+)
+
+func TestMatches(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "foo")
+	if err := os.WriteFile(path, []byte("foo"), 0644); err != nil {
+		t.Fatal("os.WriteFile() error =", err)
+	}
+	tests := []struct {
+		name string
+		path string
+		data []byte
+		want bool
+	}{
+		{name: "matched", path: path, data: []byte("foo"), want: true},
+		{name: "changed", path: path, data: []byte("bar"), want: false},
+		{name: "resized", path: path, data: []byte("foobar"), want: false},
+		{name: "missing", path: filepath.Join(dir, "bar"), data: []byte("foo"), want: false},
+		{name: "directory", path: dir, data: []byte("foo"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Matches(tt.path, content.NewDescriptorFromBytes("test", tt.data))
+			if err != nil {
+				t.Fatal("Matches() error =", err)
+			}
+			if got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"keep", "stale", "sub/keep", "sub/stale", "empty/stale", "dir/any"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+	kept := map[string]bool{"keep": true, "sub/keep": true, "dir": true}
+	removed, err := Prune(root, func(name string) bool {
+		return kept[name]
+	})
+	if err != nil {
+		t.Fatal("Prune() error =", err)
+	}
+	var got []string
+	for _, path := range removed {
+		rel, _ := filepath.Rel(root, path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	if want := []string{"empty/stale", "stale", "sub/stale"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Prune() removed %v, want %v", got, want)
+	}
+	for _, name := range []string{"keep", "sub/keep", "dir/any"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+			t.Errorf("Prune() removed kept file %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "empty")); !os.IsNotExist(err) {
+		t.Errorf("Prune() kept the empty directory, error = %v", err)
+	}
+}
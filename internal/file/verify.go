@@ -0,0 +1,152 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Entry describes a file, directory or symbolic link expected on disk.
+type Entry struct {
+	// Name is the slash-separated path relative to the root directory.
+	Name string
+	// Type is the type of the entry, which is 0 for regular files,
+	// fs.ModeDir for directories or fs.ModeSymlink for symbolic links.
+	Type fs.FileMode
+	// Digest and Size are the digest and the size of a regular file.
+	Digest digest.Digest
+	Size   int64
+	// Linkname is the target of a symbolic link.
+	Linkname string
+}
+
+// Difference statuses reported by Compare.
+const (
+	StatusMissing  = "missing"
+	StatusModified = "modified"
+	StatusExtra    = "extra"
+)
+
+// Difference is an entry differing between the expected entries and the disk.
+type Difference struct {
+	// Name is the slash-separated path relative to the root directory.
+	Name   string
+	Status string
+}
+
+// Compare compares the entries expected under root with the disk, and returns
+// the differences sorted by name. Files under root not expected are reported
+// as extra unless ignored by ignore, which is called with the slash-separated
+// paths relative to root.
+func Compare(root string, entries []Entry, ignore func(name string) bool) ([]Difference, error) {
+	var diffs []Difference
+	expected := make(map[string]bool)
+	for _, entry := range entries {
+		name := path.Clean(entry.Name)
+		if expected[name] {
+			continue
+		}
+		expected[name] = true
+		// the parent directories are expected as well
+		for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			expected[dir] = true
+		}
+		status, err := compareEntry(filepath.Join(root, filepath.FromSlash(name)), entry)
+		if err != nil {
+			return nil, err
+		}
+		if status != "" {
+			diffs = append(diffs, Difference{Name: name, Status: status})
+		}
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if expected[name] {
+			return nil
+		}
+		if ignore != nil && ignore(name) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			diffs = append(diffs, Difference{Name: name, Status: StatusExtra})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs, nil
+}
+
+// compareEntry returns the status of the entry at path, or an empty string if
+// it matches.
+func compareEntry(path string, entry Entry) (string, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return StatusMissing, nil
+		}
+		return "", err
+	}
+	if fi.Mode().Type() != entry.Type {
+		return StatusModified, nil
+	}
+	switch entry.Type {
+	case 0:
+		matched, err := Matches(path, ocispec.Descriptor{Digest: entry.Digest, Size: entry.Size})
+		if err != nil {
+			return "", err
+		}
+		if !matched {
+			return StatusModified, nil
+		}
+	case fs.ModeSymlink:
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		if filepath.ToSlash(link) != filepath.ToSlash(entry.Linkname) {
+			return StatusModified, nil
+		}
+	}
+	return "", nil
+}
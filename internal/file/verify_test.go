@@ -0,0 +1,70 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func TestCompare(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"same":            "same",
+		"modified":        "modified!",
+		"dir/same":        "same",
+		"dir/extra":       "extra",
+		"extra":           "extra",
+		"ignored/file":    "ignored",
+		"type-mismatched": "file",
+	})
+	if err := os.Symlink("same", filepath.Join(root, "dir", "link")); err != nil {
+		t.Fatal("os.Symlink() error =", err)
+	}
+	entries := []Entry{
+		{Name: "same", Digest: digest.FromString("same"), Size: 4},
+		{Name: "./same", Digest: digest.FromString("same"), Size: 4},
+		{Name: "modified", Digest: digest.FromString("modified"), Size: 8},
+		{Name: "missing", Digest: digest.FromString("missing"), Size: 7},
+		{Name: "dir", Type: fs.ModeDir},
+		{Name: "dir/same", Digest: digest.FromString("same"), Size: 4},
+		{Name: "dir/link", Type: fs.ModeSymlink, Linkname: "other"},
+		{Name: "type-mismatched", Type: fs.ModeDir},
+	}
+	got, err := Compare(root, entries, func(name string) bool {
+		return strings.HasPrefix(name, "ignored")
+	})
+	if err != nil {
+		t.Fatal("Compare() error =", err)
+	}
+	want := []Difference{
+		{Name: "dir/extra", Status: StatusExtra},
+		{Name: "dir/link", Status: StatusModified},
+		{Name: "extra", Status: StatusExtra},
+		{Name: "missing", Status: StatusMissing},
+		{Name: "modified", Status: StatusModified},
+		{Name: "type-mismatched", Status: StatusModified},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %v, want %v", got, want)
+	}
+}
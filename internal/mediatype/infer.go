@@ -0,0 +1,234 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mediatype infers the media types of files.
+package mediatype
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/klauspost/compress/zstd"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras/internal/archive"
+)
+
+// Media types of compressed files.
+const (
+	MediaTypeGzip = "application/gzip"
+	MediaTypeZstd = "application/zstd"
+)
+
+// mediaTypeRegexp checks the format of media types.
+// References:
+//   - https://github.com/opencontainers/image-spec/blob/v1.1.0/schema/defs-descriptor.json#L7
+//   - https://datatracker.ietf.org/doc/html/rfc6838#section-4.2
+var mediaTypeRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]{0,126}/[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]{0,126}$`)
+
+// sniffLen is the number of leading bytes read for content sniffing, which
+// covers the tar header of a compressed tarball.
+const sniffLen = 1024
+
+// maxJSONSniffSize is the size limit of files validated as JSON.
+const maxJSONSniffSize = 1 << 20
+
+// extensions maps the file extensions to the media types. Longer extensions
+// take precedence.
+var extensions = map[string]string{
+	".json":    "application/json",
+	".yaml":    "application/yaml",
+	".yml":     "application/yaml",
+	".toml":    "application/toml",
+	".xml":     "application/xml",
+	".wasm":    "application/wasm",
+	".txt":     "text/plain",
+	".md":      "text/markdown",
+	".html":    "text/html",
+	".csv":     "text/csv",
+	".pdf":     "application/pdf",
+	".png":     "image/png",
+	".jpg":     "image/jpeg",
+	".jpeg":    "image/jpeg",
+	".svg":     "image/svg+xml",
+	".zip":     "application/zip",
+	".gz":      MediaTypeGzip,
+	".zst":     MediaTypeZstd,
+	".tar":     ocispec.MediaTypeImageLayer,
+	".tar.gz":  ocispec.MediaTypeImageLayerGzip,
+	".tgz":     ocispec.MediaTypeImageLayerGzip,
+	".tar.zst": archive.MediaTypeImageLayerZstd,
+}
+
+// Inferrer infers the media types of files from the user-supplied mapping,
+// the file extensions and the content, in order.
+type Inferrer struct {
+	// Mapping maps file extensions starting with "." or glob patterns of file
+	// paths to media types.
+	Mapping map[string]string
+}
+
+// Infer returns the media type of the regular file at name, or an empty
+// string if the media type cannot be inferred.
+func (i *Inferrer) Infer(name string) (string, error) {
+	if mediaType := i.fromMapping(name); mediaType != "" {
+		return mediaType, nil
+	}
+	if mediaType := fromExtension(name); mediaType != "" {
+		return mediaType, nil
+	}
+	return sniff(name)
+}
+
+// fromMapping looks up the user-supplied mapping. Patterns are matched before
+// extensions, and longer keys take precedence.
+func (i *Inferrer) fromMapping(name string) string {
+	keys := make([]string, 0, len(i.Mapping))
+	for key := range i.Mapping {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if len(keys[a]) != len(keys[b]) {
+			return len(keys[a]) > len(keys[b])
+		}
+		return keys[a] < keys[b]
+	})
+	slashed := path.Clean(filepath.ToSlash(name))
+	var byExtension string
+	for _, key := range keys {
+		if strings.HasPrefix(key, ".") && !strings.Contains(key, "/") {
+			if byExtension == "" && strings.HasSuffix(strings.ToLower(slashed), strings.ToLower(key)) {
+				byExtension = i.Mapping[key]
+			}
+			continue
+		}
+		if doublestar.MatchUnvalidated(key, slashed) {
+			return i.Mapping[key]
+		}
+	}
+	return byExtension
+}
+
+// ValidateMapping validates the patterns and media types of mapping.
+func ValidateMapping(mapping map[string]string) error {
+	for key, mediaType := range mapping {
+		if !doublestar.ValidatePattern(key) {
+			return fmt.Errorf("invalid pattern %q", key)
+		}
+		if !mediaTypeRegexp.MatchString(mediaType) {
+			return fmt.Errorf("invalid media type %q for %q", mediaType, key)
+		}
+	}
+	return nil
+}
+
+// fromExtension looks up the built-in extension table.
+func fromExtension(name string) string {
+	base := strings.ToLower(filepath.Base(name))
+	ext := filepath.Ext(base)
+	if ext == "" {
+		return ""
+	}
+	// try the longer extension first, e.g. .tar.gz over .gz
+	if longer := filepath.Ext(strings.TrimSuffix(base, ext)) + ext; longer != ext {
+		if mediaType, ok := extensions[longer]; ok {
+			return mediaType
+		}
+	}
+	return extensions[ext]
+}
+
+// sniff infers the media type from the leading bytes of the file.
+func sniff(name string) (string, error) {
+	fp, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer fp.Close()
+	fi, err := fp.Stat()
+	if err != nil {
+		return "", err
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(fp, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	head = head[:n]
+	if n == 0 {
+		return "", nil
+	}
+
+	switch {
+	case isTar(head):
+		return ocispec.MediaTypeImageLayer, nil
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		if zr, err := gzip.NewReader(bytes.NewReader(head)); err == nil && isTar(readPartial(zr)) {
+			return ocispec.MediaTypeImageLayerGzip, nil
+		}
+		return MediaTypeGzip, nil
+	case bytes.HasPrefix(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		if zr, err := zstd.NewReader(bytes.NewReader(head)); err == nil {
+			defer zr.Close()
+			if isTar(readPartial(zr)) {
+				return archive.MediaTypeImageLayerZstd, nil
+			}
+		}
+		return MediaTypeZstd, nil
+	}
+
+	if trimmed := bytes.TrimSpace(head); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && fi.Size() <= maxJSONSniffSize {
+		if _, err := fp.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		if content, err := io.ReadAll(fp); err == nil && json.Valid(content) {
+			return "application/json", nil
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || mediaType == "application/octet-stream" {
+		return "", nil
+	}
+	if mediaType == "application/x-gzip" {
+		return MediaTypeGzip, nil
+	}
+	return mediaType, nil
+}
+
+// isTar returns true if head starts with a POSIX or GNU tar header.
+func isTar(head []byte) bool {
+	const magicOffset = 257
+	return len(head) >= magicOffset+5 && bytes.Equal(head[magicOffset:magicOffset+5], []byte("ustar"))
+}
+
+// readPartial reads the leading decompressed bytes, ignoring the errors
+// caused by the truncated input.
+func readPartial(r io.Reader) []byte {
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(r, buf)
+	return buf[:n]
+}
@@ -0,0 +1,142 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mediatype
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// tarball returns a tarball containing a single file.
+func tarball(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "hi.txt", Mode: 0644, Size: 2, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal("tar.Writer.WriteHeader() error =", err)
+	}
+	if _, err := tw.Write([]byte("hi")); err != nil {
+		t.Fatal("tar.Writer.Write() error =", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("tar.Writer.Close() error =", err)
+	}
+	return buf.Bytes()
+}
+
+// gzipped returns the gzip compressed data.
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	if _, err := gzw.Write(data); err != nil {
+		t.Fatal("gzip.Writer.Write() error =", err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal("gzip.Writer.Close() error =", err)
+	}
+	return buf.Bytes()
+}
+
+func TestInferrer_Infer(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"config.json":     []byte("{}"),
+		"values.YML":      []byte("a: b"),
+		"model.onnx":      []byte("\x08\x07"),
+		"layer.tar.gz":    nil,
+		"module":          []byte("\x00asm\x01\x00\x00\x00"),
+		"archive":         tarball(t),
+		"archive-gz":      gzipped(t, tarball(t)),
+		"data-gz":         gzipped(t, []byte("hello")),
+		"manifest":        []byte(` {"schemaVersion": 2}`),
+		"broken":          []byte("{not json"),
+		"unknown":         {0x01, 0x02, 0x03},
+		"empty":           nil,
+		"models/a.weight": []byte("\x01"),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+
+	inferrer := &Inferrer{
+		Mapping: map[string]string{
+			".onnx":       "application/vnd.onnx",
+			"**/models/*": "application/vnd.example.weight",
+		},
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"config.json", "application/json"},
+		{"values.YML", "application/yaml"},
+		{"model.onnx", "application/vnd.onnx"},
+		{"layer.tar.gz", ocispec.MediaTypeImageLayerGzip},
+		{"module", "application/wasm"},
+		{"archive", ocispec.MediaTypeImageLayer},
+		{"archive-gz", ocispec.MediaTypeImageLayerGzip},
+		{"data-gz", MediaTypeGzip},
+		{"manifest", "application/json"},
+		{"broken", "text/plain"},
+		{"unknown", ""},
+		{"empty", ""},
+		{"models/a.weight", "application/vnd.example.weight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inferrer.Infer(filepath.Join(dir, tt.name))
+			if err != nil {
+				t.Fatal("Inferrer.Infer() error =", err)
+			}
+			if got != tt.want {
+				t.Errorf("Inferrer.Infer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping map[string]string
+		wantErr bool
+	}{
+		{name: "valid", mapping: map[string]string{".onnx": "application/vnd.onnx", "models/**": "application/octet-stream"}},
+		{name: "invalid pattern", mapping: map[string]string{"[models": "application/octet-stream"}, wantErr: true},
+		{name: "invalid media type", mapping: map[string]string{".onnx": "onnx"}, wantErr: true},
+		{name: "media type with parameters", mapping: map[string]string{".txt": "text/plain; charset=utf-8"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMapping(tt.mapping); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
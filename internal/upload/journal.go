@@ -0,0 +1,131 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Session records the state of an interrupted chunked upload.
+type Session struct {
+	// Reference is the repository the blob is uploaded to.
+	Reference string `json:"reference"`
+	// Key identifies the uploaded content, e.g. by its digest.
+	Key string `json:"key"`
+	// Size is the size of the blob.
+	Size int64 `json:"size"`
+	// Location is the upload URL returned by the registry.
+	Location string `json:"location"`
+	// Offset is the number of bytes accepted by the registry.
+	Offset int64 `json:"offset"`
+	// HashState is the marshaled state of the hash of the first Offset
+	// bytes, if supported by the digest algorithm.
+	HashState []byte `json:"hashState,omitempty"`
+	// UpdatedAt is the time the session was last updated.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Journal persists upload sessions in a local directory, so that interrupted
+// uploads can be resumed by a later run.
+type Journal struct {
+	root string
+}
+
+// NewJournal creates a journal stored under root.
+func NewJournal(root string) *Journal {
+	return &Journal{
+		root: root,
+	}
+}
+
+// DefaultJournal returns the journal stored in the user cache directory.
+func DefaultJournal() (*Journal, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the upload journal: %w", err)
+	}
+	return NewJournal(filepath.Join(cacheDir, "oras", "uploads")), nil
+}
+
+// Load loads the session of uploading the content identified by key to
+// reference. The returned session is nil if there is none.
+func (j *Journal) Load(reference, key string) (*Session, error) {
+	data, err := os.ReadFile(j.path(reference, key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil || session.Reference != reference || session.Key != key {
+		// ignore broken records
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// Save saves the session.
+func (j *Journal) Save(session *Session) error {
+	session.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(j.root, 0700); err != nil {
+		return err
+	}
+	// write to a temporary file first so that the record is never broken
+	fp, err := os.CreateTemp(j.root, "session_*")
+	if err != nil {
+		return err
+	}
+	tmp := fp.Name()
+	_, err = fp.Write(data)
+	if closeErr := fp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, j.path(session.Reference, session.Key))
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Remove removes the session of uploading the content identified by key to
+// reference.
+func (j *Journal) Remove(reference, key string) error {
+	if err := os.Remove(j.path(reference, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file path of the session record.
+func (j *Journal) path(reference, key string) string {
+	name := digest.FromString(reference + "@" + key)
+	return filepath.Join(j.root, name.Encoded()+".json")
+}
@@ -0,0 +1,412 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upload implements resumable chunked blob uploads.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+	"oras.land/oras/internal/contentutil"
+)
+
+// defaultStreamChunkSize is the size of each chunk for uploading content of
+// unknown size if the chunk size is not specified.
+const defaultStreamChunkSize = 8 * 1024 * 1024
+
+// Repository is a remote repository pushing large blobs in chunks. Upload
+// sessions are recorded in a journal so that an interrupted upload is resumed
+// by pushing the same blob again.
+type Repository struct {
+	*remote.Repository
+	// ChunkSize is the size of each chunk. Blobs not larger than ChunkSize
+	// are pushed in a single request.
+	ChunkSize int64
+	// Journal records upload sessions.
+	Journal *Journal
+}
+
+// NewRepository wraps repo to push blobs in chunks of chunkSize.
+func NewRepository(repo *remote.Repository, chunkSize int64, journal *Journal) *Repository {
+	return &Repository{
+		Repository: repo,
+		ChunkSize:  chunkSize,
+		Journal:    journal,
+	}
+}
+
+// Push pushes the content, matching the expected descriptor.
+func (r *Repository) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	if r.ChunkSize <= 0 || expected.Size <= r.ChunkSize || isManifest(r.Repository, expected) {
+		return r.Repository.Push(ctx, expected, content)
+	}
+	if err := expected.Digest.Validate(); err != nil {
+		return err
+	}
+	_, _, err := r.upload(ctx, expected.Digest.String(), expected.Size, expected.Digest.Algorithm(), expected.Digest, content)
+	return err
+}
+
+// PushStream pushes the content as a blob, computing the digest while
+// uploading so that the content is read only once. The digest is committed at
+// the end of the upload and verified by the registry. The digest and the size
+// of expected are optional, where an unknown size is negative. If provided,
+// they are verified before the upload is committed.
+// If ChunkSize is set, the content is uploaded in chunks and an interrupted
+// upload is resumed by pushing the content identified by the same key again.
+// Content of unknown size is always uploaded in chunks.
+func (r *Repository) PushStream(ctx context.Context, expected ocispec.Descriptor, content io.Reader, key string) (ocispec.Descriptor, error) {
+	alg := digest.Canonical
+	if expected.Digest != "" {
+		if err := expected.Digest.Validate(); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		alg = expected.Digest.Algorithm()
+	}
+	if expected.Size < 0 {
+		// an unknown size cannot be resumed
+		key = ""
+	}
+	dgst, size, err := r.upload(ctx, key, expected.Size, alg, expected.Digest, content)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{
+		MediaType: expected.MediaType,
+		Digest:    dgst,
+		Size:      size,
+	}, nil
+}
+
+// isManifest determines if desc is a manifest.
+func isManifest(repo *remote.Repository, desc ocispec.Descriptor) bool {
+	mediaTypes := repo.ManifestMediaTypes
+	if mediaTypes == nil {
+		mediaTypes = []string{
+			"application/vnd.docker.distribution.manifest.v2+json",
+			"application/vnd.docker.distribution.manifest.list.v2+json",
+			ocispec.MediaTypeImageManifest,
+			ocispec.MediaTypeImageIndex,
+		}
+	}
+	for _, mediaType := range mediaTypes {
+		if desc.MediaType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// upload uploads size bytes of the content hashed by alg, and returns the
+// digest and the size. The upload is committed only if the digest matches
+// expected, unless expected is empty. If ChunkSize is set, the content is
+// uploaded in chunks and the session is recorded under key, resuming the
+// recorded session if there is one. Otherwise, the content is uploaded in a
+// single request. Content of unknown size, which is negative, is read into
+// memory chunk by chunk.
+func (r *Repository) upload(ctx context.Context, key string, size int64, alg digest.Algorithm, expected digest.Digest, r0 io.Reader) (digest.Digest, int64, error) {
+	// pushing usually requires both pull and push actions.
+	ctx = auth.AppendRepositoryScope(ctx, r.Reference, auth.ActionPull, auth.ActionPush)
+	reference := r.Reference.Registry + "/" + r.Reference.Repository
+	chunkSize := r.ChunkSize
+	resumable := chunkSize > 0 && key != "" && r.Journal != nil
+	if chunkSize <= 0 {
+		chunkSize = max(size, 1)
+		if size < 0 {
+			chunkSize = defaultStreamChunkSize
+		}
+	}
+
+	var session *Session
+	if resumable {
+		var err error
+		if session, err = r.resume(ctx, reference, key, size); err != nil {
+			return "", 0, err
+		}
+	}
+	if session == nil {
+		location, err := r.start(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		session = &Session{
+			Reference: reference,
+			Key:       key,
+			Size:      size,
+			Location:  location,
+		}
+	}
+
+	// skip the content already uploaded
+	hasher, err := skip(r0, alg, session)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var buf []byte
+	if size < 0 {
+		buf = make([]byte, chunkSize)
+	}
+	for size < 0 || session.Offset < size {
+		var n int64
+		var chunk io.Reader
+		if size < 0 {
+			// read the next chunk to know its size
+			m, err := io.ReadFull(r0, buf)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return "", 0, err
+			}
+			if m == 0 {
+				break
+			}
+			n = int64(m)
+			hasher.Write(buf[:m])
+			chunk = bytes.NewReader(buf[:m])
+		} else {
+			n = min(chunkSize, size-session.Offset)
+			chunk = io.TeeReader(io.LimitReader(r0, n), hasher)
+		}
+		location, err := r.patch(ctx, session.Location, session.Offset, n, chunk)
+		if err != nil {
+			return "", 0, err
+		}
+		session.Location = location
+		session.Offset += n
+		if resumable {
+			session.HashState = contentutil.MarshalHash(hasher)
+			if err := r.Journal.Save(session); err != nil {
+				return "", 0, fmt.Errorf("failed to record upload session: %w", err)
+			}
+		}
+	}
+
+	got := digest.NewDigest(alg, hasher)
+	if expected != "" && got != expected {
+		if resumable {
+			// the recorded session is useless for other content
+			_ = r.Journal.Remove(reference, key)
+		}
+		return "", 0, fmt.Errorf("%s: %w: got %s", expected, content.ErrMismatchedDigest, got)
+	}
+	if err := r.complete(ctx, session.Location, got); err != nil {
+		return "", 0, err
+	}
+	if resumable {
+		if err := r.Journal.Remove(reference, key); err != nil {
+			return "", 0, err
+		}
+	}
+	return got, session.Offset, nil
+}
+
+// resume loads the recorded session and queries the registry for its
+// progress. The returned session is nil if there is no session to resume.
+func (r *Repository) resume(ctx context.Context, reference, key string, size int64) (*Session, error) {
+	session, err := r.Journal.Load(reference, key)
+	if err != nil || session == nil || session.Size != size {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, session.Location, nil)
+	if err != nil {
+		return nil, nil
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		// the session has expired or is unknown to the registry
+		_ = r.Journal.Remove(reference, key)
+		return nil, nil
+	}
+	offset, err := parseRange(resp.Header.Get("Range"), session.Offset)
+	if err != nil || offset > size {
+		_ = r.Journal.Remove(reference, key)
+		return nil, nil
+	}
+	if offset != session.Offset {
+		// the recorded hash state does not match the progress
+		session.Offset = offset
+		session.HashState = nil
+	}
+	if location, err := resp.Location(); err == nil {
+		session.Location = location.String()
+	}
+	return session, nil
+}
+
+// start starts a new upload session and returns its location.
+func (r *Repository) start(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("%s://%s/v2/%s/blobs/uploads/", scheme(r.PlainHTTP), r.Reference.Host(), r.Reference.Repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", parseErrorResponse(resp)
+	}
+	return resolveLocation(req, resp)
+}
+
+// patch uploads a chunk of size n at offset and returns the location of the
+// next request.
+func (r *Repository) patch(ctx context.Context, location string, offset, n int64, chunk io.Reader) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, location, chunk)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = n
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", offset, offset+n-1))
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", parseErrorResponse(resp)
+	}
+	return resolveLocation(req, resp)
+}
+
+// complete completes the upload session.
+func (r *Repository) complete(ctx context.Context, location string, dgst digest.Digest) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location, nil)
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	q.Set("digest", dgst.String())
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return parseErrorResponse(resp)
+	}
+	return nil
+}
+
+// client returns the HTTP client of the repository.
+func (r *Repository) client() remote.Client {
+	if r.Client == nil {
+		return auth.DefaultClient
+	}
+	return r.Client
+}
+
+// skip skips the first session.Offset bytes of r, which are already
+// uploaded, and returns the hash of them.
+func skip(r io.Reader, alg digest.Algorithm, session *Session) (hash.Hash, error) {
+	hasher := alg.Hash()
+	if session.Offset == 0 {
+		return hasher, nil
+	}
+	if seeker, ok := r.(io.Seeker); ok && contentutil.UnmarshalHash(hasher, session.HashState) {
+		if _, err := seeker.Seek(session.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return hasher, nil
+	}
+	hasher.Reset()
+	if _, err := io.CopyN(hasher, r, session.Offset); err != nil {
+		return nil, err
+	}
+	return hasher, nil
+}
+
+// parseRange parses the Range header of an upload status response and
+// returns the number of bytes received. Some registries report "0-0" for
+// sessions without any bytes received, so "0-0" is only taken as 1 byte
+// received if recorded, the offset in the journal, is 1.
+func parseRange(value string, recorded int64) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	_, end, ok := strings.Cut(strings.TrimPrefix(value, "bytes="), "-")
+	if !ok {
+		return 0, fmt.Errorf("invalid range %q", value)
+	}
+	n, err := strconv.ParseInt(end, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid range %q", value)
+	}
+	if n == 0 && recorded != 1 {
+		return 0, nil
+	}
+	return n + 1, nil
+}
+
+// resolveLocation returns the absolute URL in the Location header of resp.
+func resolveLocation(req *http.Request, resp *http.Response) (string, error) {
+	loc, err := resp.Location()
+	if err != nil {
+		return "", err
+	}
+	// some registries drop the explicit port 443 in the location.
+	// Reference: https://github.com/oras-project/oras-go/issues/177
+	if port := req.URL.Port(); port == "443" && loc.Hostname() == req.URL.Hostname() && loc.Port() == "" {
+		loc.Host = loc.Hostname() + ":" + port
+	}
+	return loc.String(), nil
+}
+
+// scheme returns the URL scheme for the registry.
+func scheme(plainHTTP bool) string {
+	if plainHTTP {
+		return "http"
+	}
+	return "https"
+}
+
+// parseErrorResponse parses the error returned by the registry.
+func parseErrorResponse(resp *http.Response) error {
+	errResp := &errcode.ErrorResponse{
+		Method:     resp.Request.Method,
+		URL:        resp.Request.URL,
+		StatusCode: resp.StatusCode,
+	}
+	var body struct {
+		Errors errcode.Errors `json:"errors"`
+	}
+	lr := io.LimitReader(resp.Body, 8*1024)
+	if err := json.NewDecoder(lr).Decode(&body); err == nil {
+		errResp.Errors = body.Errors
+	}
+	return errResp
+}
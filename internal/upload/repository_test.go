@@ -0,0 +1,353 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// mockRegistry is a registry supporting chunked uploads, which fails the
+// PATCH requests listed in failPatches.
+type mockRegistry struct {
+	mu          sync.Mutex
+	uploads     map[string][]byte
+	blobs       map[digest.Digest][]byte
+	posts       int
+	patches     int
+	failPatches map[int]bool
+}
+
+func (m *mockRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	const prefix = "/v2/test/blobs/uploads/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, prefix)
+	data, ok := m.uploads[id]
+	if id != "" && !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		m.posts++
+		id = fmt.Sprint(m.posts)
+		m.uploads[id] = nil
+		w.Header().Set("Location", prefix+id)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPatch:
+		m.patches++
+		if m.failPatches[m.patches] {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if want := fmt.Sprintf("%d-%d", len(data), len(data)+int(r.ContentLength)-1); r.Header.Get("Content-Range") != want {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		chunk, _ := io.ReadAll(r.Body)
+		m.uploads[id] = append(data, chunk...)
+		w.Header().Set("Location", prefix+id)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodGet:
+		w.Header().Set("Location", prefix+id)
+		// empty sessions are reported as "0-0" like the distribution registry
+		w.Header().Set("Range", fmt.Sprintf("0-%d", max(len(data)-1, 0)))
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPut:
+		dgst := digest.Digest(r.URL.Query().Get("digest"))
+		if digest.FromBytes(data) != dgst {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.blobs[dgst] = data
+		delete(m.uploads, id)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestRepository(t *testing.T, m *mockRegistry, chunkSize int64) *Repository {
+	t.Helper()
+	ts := httptest.NewServer(m)
+	t.Cleanup(ts.Close)
+	uri, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid test http server: %v", err)
+	}
+	repo, err := remote.NewRepository(uri.Host + "/test")
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	repo.PlainHTTP = true
+	return NewRepository(repo, chunkSize, NewJournal(t.TempDir()))
+}
+
+func TestRepository_Push_resume(t *testing.T) {
+	blob := []byte("hello world, this is a chunked upload")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	tests := []struct {
+		name   string
+		reader func() io.Reader
+	}{
+		{
+			name:   "seekable",
+			reader: func() io.Reader { return bytes.NewReader(blob) },
+		},
+		{
+			name:   "non-seekable",
+			reader: func() io.Reader { return io.MultiReader(bytes.NewReader(blob)) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockRegistry{
+				uploads:     make(map[string][]byte),
+				blobs:       make(map[digest.Digest][]byte),
+				failPatches: map[int]bool{3: true},
+			}
+			r := newTestRepository(t, m, 10)
+			ctx := context.Background()
+
+			// the first push is interrupted after 2 chunks
+			if err := r.Push(ctx, desc, tt.reader()); err == nil {
+				t.Fatal("Repository.Push() error = nil, want error")
+			}
+			reference := r.Reference.Registry + "/test"
+			session, err := r.Journal.Load(reference, desc.Digest.String())
+			if err != nil || session == nil {
+				t.Fatalf("Journal.Load() = %v, %v, want recorded session", session, err)
+			}
+			if session.Offset != 20 {
+				t.Errorf("recorded offset = %d, want %d", session.Offset, 20)
+			}
+
+			// the second push resumes the upload
+			if err := r.Push(ctx, desc, tt.reader()); err != nil {
+				t.Fatal("Repository.Push() error =", err)
+			}
+			if m.posts != 1 {
+				t.Errorf("upload started %d times, want 1", m.posts)
+			}
+			if got := m.blobs[desc.Digest]; !bytes.Equal(got, blob) {
+				t.Errorf("uploaded blob = %q, want %q", got, blob)
+			}
+			if session, err := r.Journal.Load(reference, desc.Digest.String()); err != nil || session != nil {
+				t.Errorf("Journal.Load() = %v, %v, want no session", session, err)
+			}
+		})
+	}
+}
+
+func TestRepository_Push_resumeAmbiguousRange(t *testing.T) {
+	blob := []byte("hello world")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	tests := []struct {
+		name      string
+		chunkSize int64
+		// lost drops the bytes received by the registry before resuming
+		lost bool
+	}{
+		{
+			name:      "1 byte received",
+			chunkSize: 1,
+		},
+		{
+			name:      "no bytes received",
+			chunkSize: 5,
+			lost:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockRegistry{
+				uploads:     make(map[string][]byte),
+				blobs:       make(map[digest.Digest][]byte),
+				failPatches: map[int]bool{2: true},
+			}
+			r := newTestRepository(t, m, tt.chunkSize)
+			ctx := context.Background()
+
+			// the first push is interrupted after 1 chunk
+			if err := r.Push(ctx, desc, bytes.NewReader(blob)); err == nil {
+				t.Fatal("Repository.Push() error = nil, want error")
+			}
+			if tt.lost {
+				for id := range m.uploads {
+					m.uploads[id] = nil
+				}
+			}
+
+			// the second push resumes the upload from the offset reported
+			// as "0-0"
+			if err := r.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+				t.Fatal("Repository.Push() error =", err)
+			}
+			if m.posts != 1 {
+				t.Errorf("upload started %d times, want 1", m.posts)
+			}
+			if got := m.blobs[desc.Digest]; !bytes.Equal(got, blob) {
+				t.Errorf("uploaded blob = %q, want %q", got, blob)
+			}
+		})
+	}
+}
+
+func TestRepository_Push_expiredSession(t *testing.T) {
+	blob := []byte("hello world, this is a chunked upload")
+	desc := content.NewDescriptorFromBytes("test", blob)
+	m := &mockRegistry{
+		uploads: make(map[string][]byte),
+		blobs:   make(map[digest.Digest][]byte),
+	}
+	r := newTestRepository(t, m, 10)
+	if err := r.Journal.Save(&Session{
+		Reference: r.Reference.Registry + "/test",
+		Key:       desc.Digest.String(),
+		Size:      desc.Size,
+		Location:  fmt.Sprintf("http://%s/v2/test/blobs/uploads/expired", r.Reference.Registry),
+		Offset:    20,
+	}); err != nil {
+		t.Fatal("Journal.Save() error =", err)
+	}
+
+	if err := r.Push(context.Background(), desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal("Repository.Push() error =", err)
+	}
+	if m.posts != 1 {
+		t.Errorf("upload started %d times, want 1", m.posts)
+	}
+	if got := m.blobs[desc.Digest]; !bytes.Equal(got, blob) {
+		t.Errorf("uploaded blob = %q, want %q", got, blob)
+	}
+}
+
+func TestRepository_Push_mismatchedDigest(t *testing.T) {
+	blob := []byte("hello world, this is a chunked upload")
+	desc := ocispec.Descriptor{
+		MediaType: "test",
+		Digest:    digest.FromString("something else"),
+		Size:      int64(len(blob)),
+	}
+	m := &mockRegistry{
+		uploads: make(map[string][]byte),
+		blobs:   make(map[digest.Digest][]byte),
+	}
+	r := newTestRepository(t, m, 10)
+	if err := r.Push(context.Background(), desc, bytes.NewReader(blob)); err == nil {
+		t.Fatal("Repository.Push() error = nil, want error")
+	}
+	if len(m.blobs) != 0 {
+		t.Errorf("uploaded %d blobs, want 0", len(m.blobs))
+	}
+}
+
+func TestRepository_PushStream(t *testing.T) {
+	blob := []byte("hello world, this is a streamed upload")
+	want := content.NewDescriptorFromBytes("test", blob)
+	tests := []struct {
+		name      string
+		chunkSize int64
+		expected  ocispec.Descriptor
+		wantPatch int
+		wantErr   bool
+	}{
+		{
+			name:      "monolithic",
+			expected:  ocispec.Descriptor{MediaType: "test", Size: want.Size},
+			wantPatch: 1,
+		},
+		{
+			name:      "chunked",
+			chunkSize: 10,
+			expected:  ocispec.Descriptor{MediaType: "test", Size: want.Size},
+			wantPatch: 4,
+		},
+		{
+			name:      "unknown size",
+			expected:  ocispec.Descriptor{MediaType: "test", Size: -1},
+			wantPatch: 1,
+		},
+		{
+			name:      "unknown size chunked",
+			chunkSize: 10,
+			expected:  ocispec.Descriptor{MediaType: "test", Size: -1},
+			wantPatch: 4,
+		},
+		{
+			name:      "expected digest",
+			chunkSize: 10,
+			expected:  ocispec.Descriptor{MediaType: "test", Digest: want.Digest, Size: -1},
+			wantPatch: 4,
+		},
+		{
+			name:      "mismatched digest",
+			expected:  ocispec.Descriptor{MediaType: "test", Digest: digest.FromString("foo"), Size: -1},
+			wantPatch: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockRegistry{
+				uploads: make(map[string][]byte),
+				blobs:   make(map[digest.Digest][]byte),
+			}
+			r := newTestRepository(t, m, tt.chunkSize)
+			got, err := r.PushStream(context.Background(), tt.expected, bytes.NewReader(blob), "key")
+			if m.patches != tt.wantPatch {
+				t.Errorf("uploaded in %d requests, want %d", m.patches, tt.wantPatch)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Repository.PushStream() error = nil, want error")
+				}
+				if len(m.blobs) != 0 {
+					t.Errorf("committed %d blobs, want 0", len(m.blobs))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Repository.PushStream() error =", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Repository.PushStream() = %v, want %v", got, want)
+			}
+			if got := m.blobs[want.Digest]; !bytes.Equal(got, blob) {
+				t.Errorf("uploaded blob = %q, want %q", got, blob)
+			}
+		})
+	}
+}
@@ -46,6 +46,7 @@ type ExecOption struct {
 	binary  string
 	args    []string
 	workDir string
+	env     []string
 	timeout time.Duration
 
 	stdin    io.Reader
@@ -102,6 +103,12 @@ func (opts *ExecOption) WithWorkDir(path string) *ExecOption {
 	return opts
 }
 
+// WithEnv sets an environment variable for the execution.
+func (opts *ExecOption) WithEnv(key string, value string) *ExecOption {
+	opts.env = append(opts.env, key+"="+value)
+	return opts
+}
+
 // WithInput redirects stdin to r for the execution.
 func (opts *ExecOption) WithInput(r io.Reader) *ExecOption {
 	opts.stdin = r
@@ -189,6 +196,9 @@ func (opts *ExecOption) Exec() *gexec.Session {
 	}
 	cmd = exec.Command(opts.binary, opts.args...)
 	cmd.Stdin = opts.stdin
+	if len(opts.env) > 0 {
+		cmd.Env = append(os.Environ(), opts.env...)
+	}
 	if opts.workDir != "" {
 		// switch working directory
 		wd, err := os.Getwd()
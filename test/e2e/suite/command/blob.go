@@ -53,14 +53,6 @@ var _ = Describe("ORAS beginners:", func() {
 					MatchErrKeyWords("Error: `-` read file from input and `--identity-token-stdin` read identity token from input cannot be both used").Exec()
 			})
 
-			It("should fail to push a blob from stdin but no blob size provided", func() {
-				repo := fmt.Sprintf(repoFmt, "push", "no-size")
-				ORAS("blob", "push", RegistryRef(ZOTHost, repo, pushDigest), "-").
-					WithInput(strings.NewReader(pushContent)).
-					ExpectFailure().
-					MatchErrKeyWords("Error: `--size` must be provided if the blob is read from stdin").Exec()
-			})
-
 			It("should fail to push a blob from stdin if invalid blob size provided", func() {
 				content := "another-test"
 				digest := "sha256:c897eff15c4586525388034f8246346681cb48d75a619039c566c4939a18102e"
@@ -249,6 +241,15 @@ var _ = Describe("1.1 registry users:", func() {
 				MatchContent(fmt.Sprintf(pushDescFmt, mediaType)).Exec()
 			ORAS("blob", "fetch", RegistryRef(ZOTHost, repo, pushDigest), "--output", "-").MatchContent(pushContent).Exec()
 		})
+
+		It("should push a blob from stdin with unknown size and digest and output the computed descriptor", func() {
+			mediaType := "test.media"
+			repo := fmt.Sprintf(repoFmt, "push", "blob-stdin-unknown")
+			ORAS("blob", "push", RegistryRef(ZOTHost, repo, ""), "-", "--media-type", mediaType, "--descriptor").
+				WithInput(strings.NewReader(pushContent)).
+				MatchContent(fmt.Sprintf(pushDescFmt, mediaType)).Exec()
+			ORAS("blob", "fetch", RegistryRef(ZOTHost, repo, pushDigest), "--output", "-").MatchContent(pushContent).Exec()
+		})
 	})
 
 	When("running `blob fetch`", func() {
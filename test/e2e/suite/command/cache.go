@@ -0,0 +1,125 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	"oras.land/oras/test/e2e/internal/testdata/feature"
+	"oras.land/oras/test/e2e/internal/testdata/foobar"
+	. "oras.land/oras/test/e2e/internal/utils"
+)
+
+const cacheRootEnv = "ORAS_CACHE"
+
+var _ = Describe("ORAS beginners:", func() {
+	When("running cache command", func() {
+		It("should show help description with feature mark", func() {
+			for _, cmd := range []string{"prune", "verify"} {
+				out := ORAS("cache", cmd, "--help").MatchKeyWords(ExampleDesc).Exec().Out
+				gomega.Expect(out.Contents()).Should(gomega.HavePrefix(feature.Experimental.Mark))
+			}
+		})
+
+		It("should fail if the cache root is not specified", func() {
+			ORAS("cache", "verify").
+				WithEnv(cacheRootEnv, "").
+				ExpectFailure().
+				MatchErrKeyWords("Error:", "cache root is not specified", cacheRootEnv).
+				Exec()
+		})
+
+		It("should fail to prune without any limit", func() {
+			ORAS("cache", "prune").
+				WithEnv(cacheRootEnv, GinkgoT().TempDir()).
+				ExpectFailure().
+				MatchErrKeyWords("Error:", "at least one of `--max-size` and `--older-than` must be provided").
+				Exec()
+		})
+	})
+})
+
+var _ = Describe("OCI image layout users:", func() {
+	When("managing the local cache", func() {
+		// populate pulls foobar via a new cache and returns the cache root.
+		populate := func() string {
+			cacheRoot := GinkgoT().TempDir()
+			root := PrepareTempOCI(ImageRepo)
+			ORAS("pull", Flags.Layout, LayoutRef(root, foobar.Tag), "-o", GinkgoT().TempDir()).
+				WithEnv(cacheRootEnv, cacheRoot).
+				Exec()
+			return cacheRoot
+		}
+		barDigest := digest.FromString("bar")
+
+		It("should verify cached blobs", func() {
+			cacheRoot := populate()
+			Expect(filepath.Join(cacheRoot, "blobs", "sha256", barDigest.Encoded())).Should(BeAnExistingFile())
+			ORAS("cache", "verify").
+				WithEnv(cacheRootEnv, cacheRoot).
+				MatchKeyWords("Verified", "0 corrupted").
+				Exec()
+		})
+
+		It("should report and evict corrupted blobs", func() {
+			cacheRoot := populate()
+			path := filepath.Join(cacheRoot, "blobs", "sha256", barDigest.Encoded())
+			Expect(os.Chmod(path, 0644)).ShouldNot(HaveOccurred())
+			Expect(os.WriteFile(path, []byte("BAR"), 0644)).ShouldNot(HaveOccurred())
+			ORAS("cache", "verify").
+				WithEnv(cacheRootEnv, cacheRoot).
+				ExpectFailure().
+				MatchKeyWords("Corrupted", barDigest.String(), "1 corrupted").
+				MatchErrKeyWords("found 1 corrupted blobs", "--evict").
+				Exec()
+			ORAS("cache", "verify", "--evict").
+				WithEnv(cacheRootEnv, cacheRoot).
+				MatchKeyWords("Corrupted", "Evicted", barDigest.String()).
+				Exec()
+			Expect(path).ShouldNot(BeAnExistingFile())
+			ORAS("cache", "verify").
+				WithEnv(cacheRootEnv, cacheRoot).
+				MatchKeyWords("Verified", "0 corrupted").
+				Exec()
+		})
+
+		It("should prune the cache to the max size", func() {
+			cacheRoot := populate()
+			ORAS("cache", "prune", "--max-size", "1B").
+				WithEnv(cacheRootEnv, cacheRoot).
+				MatchKeyWords("Evicted", barDigest.String(), "Reclaimed").
+				Exec()
+			ORAS("cache", "verify").
+				WithEnv(cacheRootEnv, cacheRoot).
+				MatchKeyWords("Verified 0 blobs, 0 corrupted").
+				Exec()
+		})
+
+		It("should keep recently used blobs when pruning by age", func() {
+			cacheRoot := populate()
+			ORAS("cache", "prune", "--older-than", "1h").
+				WithEnv(cacheRootEnv, cacheRoot).
+				MatchKeyWords("Reclaimed", "from 0 blobs").
+				Exec()
+			Expect(filepath.Join(cacheRoot, "blobs", "sha256", barDigest.Encoded())).Should(BeAnExistingFile())
+		})
+	})
+})
@@ -408,6 +408,34 @@ var _ = Describe("OCI image layout users:", func() {
 			ORAS("pull", Flags.Layout, LayoutRef(root, multi_arch.Tag), "--platform", "linux/amd64", "-v", "-o", root).
 				MatchStatus(multi_arch.LinuxAMD64StateKeys, true, len(multi_arch.LinuxAMD64StateKeys)).Exec()
 		})
+
+		It("should fail to prune without the output directory specified", func() {
+			root := PrepareTempOCI(ImageRepo)
+			stale := filepath.Join(root, "stale")
+			Expect(os.WriteFile(stale, []byte("stale"), 0644)).ShouldNot(HaveOccurred())
+			ORAS("pull", Flags.Layout, LayoutRef(root, foobar.Tag), "--prune").
+				WithWorkDir(root).
+				ExpectFailure().
+				MatchErrKeyWords("Error:", "`--prune` requires the output directory", "--output").
+				Exec()
+			Expect(stale).Should(BeAnExistingFile())
+		})
+
+		It("should remove the files not in the artifact when pruning", func() {
+			root := PrepareTempOCI(ImageRepo)
+			pullRoot := GinkgoT().TempDir()
+			stale := filepath.Join(pullRoot, "sub", "stale")
+			Expect(os.MkdirAll(filepath.Dir(stale), 0755)).ShouldNot(HaveOccurred())
+			Expect(os.WriteFile(stale, []byte("stale"), 0644)).ShouldNot(HaveOccurred())
+			ORAS("pull", Flags.Layout, LayoutRef(root, foobar.Tag), "--prune", "-o", pullRoot).
+				MatchKeyWords("Removed", stale).
+				Exec()
+			Expect(stale).ShouldNot(BeAnExistingFile())
+			for _, f := range foobar.ImageLayerNames {
+				Binary("diff", filepath.Join(root, "foobar", f), filepath.Join(pullRoot, f)).
+					WithDescription("should download identical file " + f).Exec()
+			}
+		})
 	})
 })
 
@@ -18,8 +18,10 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
@@ -151,7 +153,7 @@ var _ = Describe("Remote registry users:", func() {
 			fetched := ORAS("manifest", "fetch", ref).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 		})
 
 		It("should push files with path validation disabled", func() {
@@ -166,14 +168,15 @@ var _ = Describe("Remote registry users:", func() {
 			fetched := ORAS("manifest", "fetch", ref).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(ocispec.Descriptor{
-				MediaType: "application/vnd.oci.image.layer.v1.tar",
-				Digest:    digest.Digest(foobar.BarBlobDigest),
-				Size:      3,
-				Annotations: map[string]string{
-					"org.opencontainers.image.title": absBarName,
-				},
-			}))
+			Expect(manifest.Layers).Should(HaveLen(1))
+			layer := manifest.Layers[0]
+			Expect(layer.MediaType).Should(Equal("application/vnd.oci.image.layer.v1.tar"))
+			Expect(layer.Digest).Should(Equal(digest.Digest(foobar.BarBlobDigest)))
+			Expect(layer.Size).Should(Equal(int64(3)))
+			Expect(layer.Annotations).Should(HaveKeyWithValue("org.opencontainers.image.title", absBarName))
+			// file metadata is recorded for restoring on pull
+			Expect(layer.Annotations).Should(HaveKey("land.oras.file.mode"))
+			Expect(layer.Annotations).Should(HaveKey("land.oras.file.mtime"))
 		})
 
 		It("should fail path validation when pushing file with absolute path", func() {
@@ -200,11 +203,11 @@ var _ = Describe("Remote registry users:", func() {
 			fetched := ORAS("manifest", "fetch", RegistryRef(ZOTHost, repo, tag)).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 
 			fetched = ORAS("manifest", "fetch", RegistryRef(ZOTHost, repo, extraTag)).Exec().Out.Contents()
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 		})
 
 		It("should push and tag and hide tag logs", func() {
@@ -229,7 +232,7 @@ var _ = Describe("Remote registry users:", func() {
 			fetched := ORAS("manifest", "fetch", RegistryRef(ZOTHost, repo, tag)).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor(layerType)))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor(layerType)))
 		})
 
 		It("should push files with manifest exported", func() {
@@ -423,7 +426,7 @@ var _ = Describe("Remote registry users:", func() {
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
 			Expect(manifest.ArtifactType).Should(Equal("application/vnd.unknown.artifact.v1"))
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 			Expect(manifest.Config).Should(Equal(artifact.EmptyLayerJSON))
 		})
 
@@ -459,7 +462,7 @@ var _ = Describe("Remote registry users:", func() {
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
 			Expect(manifest.ArtifactType).Should(Equal("application/vnd.unknown.artifact.v1"))
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 			Expect(manifest.Config).Should(Equal(artifact.EmptyLayerJSON))
 		})
 
@@ -480,7 +483,7 @@ var _ = Describe("Remote registry users:", func() {
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
 			Expect(manifest.ArtifactType).Should(Equal(""))
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 			Expect(manifest.Config.MediaType).Should(Equal(configType))
 			Expect(manifest.Config.Digest).Should(Equal(foobar.FileConfigDigest))
 		})
@@ -503,7 +506,7 @@ var _ = Describe("Remote registry users:", func() {
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
 			Expect(manifest.ArtifactType).Should(Equal(artifactType))
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 			Expect(manifest.Config.MediaType).Should(Equal(configType))
 			Expect(manifest.Config.Digest).Should(Equal(foobar.FileConfigDigest))
 		})
@@ -529,7 +532,7 @@ var _ = Describe("OCI image layout users:", func() {
 			fetched := ORAS("manifest", "fetch", Flags.Layout, ref).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 		})
 
 		It("should push files and tag", func() {
@@ -545,11 +548,11 @@ var _ = Describe("OCI image layout users:", func() {
 			fetched := ORAS("manifest", "fetch", Flags.Layout, ref).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 
 			fetched = ORAS("manifest", "fetch", Flags.Layout, LayoutRef(tempDir, extraTag)).Exec().Out.Contents()
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor("application/vnd.oci.image.layer.v1.tar")))
 		})
 
 		It("should push files with customized media types", func() {
@@ -563,7 +566,7 @@ var _ = Describe("OCI image layout users:", func() {
 			fetched := ORAS("manifest", "fetch", Flags.Layout, ref).Exec().Out.Contents()
 			var manifest ocispec.Manifest
 			Expect(json.Unmarshal(fetched, &manifest)).ShouldNot(HaveOccurred())
-			Expect(manifest.Layers).Should(ContainElements(foobar.BlobBarDescriptor(layerType)))
+			Expect(withoutFileMetadata(manifest.Layers)).Should(ContainElements(foobar.BlobBarDescriptor(layerType)))
 		})
 
 		It("should push files with manifest exported", func() {
@@ -708,3 +711,17 @@ var _ = Describe("OCI image layout users:", func() {
 		})
 	})
 })
+
+// withoutFileMetadata returns the layers without the annotations of the file
+// metadata recorded on push, which vary with the test environment.
+func withoutFileMetadata(layers []ocispec.Descriptor) []ocispec.Descriptor {
+	var ret []ocispec.Descriptor
+	for _, layer := range layers {
+		layer.Annotations = maps.Clone(layer.Annotations)
+		maps.DeleteFunc(layer.Annotations, func(key string, _ string) bool {
+			return strings.HasPrefix(key, "land.oras.file.")
+		})
+		ret = append(ret, layer)
+	}
+	return ret
+}
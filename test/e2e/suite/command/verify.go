@@ -0,0 +1,140 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"oras.land/oras/test/e2e/internal/testdata/feature"
+	"oras.land/oras/test/e2e/internal/testdata/foobar"
+	. "oras.land/oras/test/e2e/internal/utils"
+)
+
+var _ = Describe("ORAS beginners:", func() {
+	When("running verify command", func() {
+		It("should show help description with feature mark", func() {
+			out := ORAS("verify", "--help").MatchKeyWords(ExampleDesc).Exec().Out
+			gomega.Expect(out.Contents()).Should(gomega.HavePrefix(feature.Experimental.Mark))
+		})
+
+		It("should fail if no directory is provided", func() {
+			ORAS("verify", RegistryRef(ZOTHost, ImageRepo, foobar.Tag)).
+				ExpectFailure().
+				MatchErrKeyWords("Error:", "the artifact reference and the directory to verify").
+				Exec()
+		})
+
+		It("should fail if the directory does not exist", func() {
+			root := PrepareTempOCI(ImageRepo)
+			dir := filepath.Join(GinkgoT().TempDir(), "missing")
+			ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir).
+				ExpectFailure().
+				MatchErrKeyWords("Error:", "failed to verify", dir).
+				Exec()
+		})
+	})
+})
+
+var _ = Describe("OCI image layout users:", func() {
+	When("verifying pulled files", func() {
+		pull := func() (root string, dir string) {
+			root = PrepareTempOCI(ImageRepo)
+			dir = GinkgoT().TempDir()
+			ORAS("pull", Flags.Layout, LayoutRef(root, foobar.Tag), "-o", dir).Exec()
+			return root, dir
+		}
+
+		It("should verify the files matching the artifact", func() {
+			root, dir := pull()
+			ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir).
+				MatchKeyWords("Verified", dir, "Digest:", foobar.Digest).
+				Exec()
+		})
+
+		It("should report missing files", func() {
+			root, dir := pull()
+			missing := filepath.Join(dir, foobar.ImageLayerNames[0])
+			Expect(os.Remove(missing)).ShouldNot(HaveOccurred())
+			ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir).
+				ExpectFailure().
+				MatchKeyWords("Missing", missing).
+				MatchErrKeyWords("1 files in", "differ from", "Pull the artifact again").
+				Exec()
+		})
+
+		It("should report modified files", func() {
+			root, dir := pull()
+			modified := filepath.Join(dir, foobar.ImageLayerNames[2])
+			Expect(os.WriteFile(modified, []byte("modified"), 0644)).ShouldNot(HaveOccurred())
+			ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir).
+				ExpectFailure().
+				MatchKeyWords("Modified", modified).
+				MatchErrKeyWords("1 files in", "differ from").
+				Exec()
+		})
+
+		It("should report extra files", func() {
+			root, dir := pull()
+			extra := filepath.Join(dir, "sub", "extra")
+			Expect(os.MkdirAll(filepath.Dir(extra), 0755)).ShouldNot(HaveOccurred())
+			Expect(os.WriteFile(extra, []byte("extra"), 0644)).ShouldNot(HaveOccurred())
+			ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir).
+				ExpectFailure().
+				MatchKeyWords("Extra", extra).
+				MatchErrKeyWords("1 files in", "differ from").
+				Exec()
+		})
+
+		It("should report the differences in json", func() {
+			root, dir := pull()
+			Expect(os.Remove(filepath.Join(dir, foobar.ImageLayerNames[0]))).ShouldNot(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, foobar.ImageLayerNames[1]), []byte("modified"), 0644)).ShouldNot(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "extra"), []byte("extra"), 0644)).ShouldNot(HaveOccurred())
+			out := ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir, "--format", "json").
+				ExpectFailure().
+				MatchErrKeyWords("3 files in", "differ from").
+				Exec().Out.Contents()
+			var verified struct {
+				Reference string   `json:"reference"`
+				Path      string   `json:"path"`
+				Missing   []string `json:"missing"`
+				Modified  []string `json:"modified"`
+				Extra     []string `json:"extra"`
+			}
+			Expect(json.Unmarshal(out, &verified)).ShouldNot(HaveOccurred())
+			Expect(verified.Reference).To(Equal(LayoutRef(root, foobar.Digest)))
+			Expect(verified.Path).To(Equal(dir))
+			Expect(verified.Missing).To(Equal([]string{filepath.Join(dir, foobar.ImageLayerNames[0])}))
+			Expect(verified.Modified).To(Equal([]string{filepath.Join(dir, foobar.ImageLayerNames[1])}))
+			Expect(verified.Extra).To(Equal([]string{filepath.Join(dir, "extra")}))
+		})
+
+		It("should output empty differences in json for matching files", func() {
+			root, dir := pull()
+			out := ORAS("verify", Flags.Layout, LayoutRef(root, foobar.Tag), dir, "--format", "json").Exec().Out.Contents()
+			var verified map[string]any
+			Expect(json.Unmarshal(out, &verified)).ShouldNot(HaveOccurred())
+			for _, key := range []string{"missing", "modified", "extra"} {
+				Expect(verified).To(HaveKeyWithValue(key, BeEmpty()))
+			}
+		})
+	})
+})